@@ -0,0 +1,2 @@
+ALTER TABLE transaction ALTER COLUMN amount TYPE NUMERIC(20,2) USING round(amount::numeric, 2);
+ALTER TABLE balance ALTER COLUMN balance TYPE NUMERIC(20,2) USING round(balance::numeric, 2);
@@ -0,0 +1,6 @@
+-- balances and transactions made before wallets were introduced are EUR
+ALTER TABLE transaction ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'EUR';
+ALTER TABLE transaction ALTER COLUMN currency DROP DEFAULT;
+ALTER TABLE balance ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'EUR';
+ALTER TABLE balance ALTER COLUMN currency DROP DEFAULT;
+ALTER TABLE balance DROP CONSTRAINT balance_pkey, ADD PRIMARY KEY (account_id, currency);
@@ -0,0 +1,4 @@
+ALTER TABLE transaction ADD COLUMN to_amount NUMERIC(20,2), ADD COLUMN to_currency CHAR(3), ADD COLUMN rate NUMERIC(20,8), ADD COLUMN quote_id BIGINT;
+UPDATE transaction SET to_amount = amount, to_currency = currency, rate = 1;
+ALTER TABLE transaction ALTER COLUMN to_amount SET NOT NULL, ALTER COLUMN to_currency SET NOT NULL, ALTER COLUMN rate SET NOT NULL;
+CREATE TABLE quote (id SERIAL PRIMARY KEY, currency CHAR(3) NOT NULL, to_currency CHAR(3) NOT NULL, amount NUMERIC(20,2) NOT NULL, to_amount NUMERIC(20,2) NOT NULL, rate NUMERIC(20,8) NOT NULL, expires_at TIMESTAMP NOT NULL, used BOOLEAN NOT NULL DEFAULT FALSE);
@@ -0,0 +1,2 @@
+CREATE TABLE idempotency_key (key VARCHAR(255) NOT NULL, operation VARCHAR(20) NOT NULL, request_hash CHAR(64) NOT NULL, response JSONB, created_at TIMESTAMP NOT NULL, PRIMARY KEY (key, operation));
+CREATE INDEX idempotency_key_created_at_idx ON idempotency_key (created_at);
@@ -0,0 +1,4 @@
+CREATE TABLE posting (id SERIAL PRIMARY KEY, transaction_id BIGINT, account_id BIGINT NOT NULL, currency CHAR(3) NOT NULL, amount NUMERIC(20,2) NOT NULL, date TIMESTAMP NOT NULL);
+CREATE INDEX posting_account_id_idx ON posting (account_id, currency);
+-- opening posting of every existing wallet keeps ledger balance equal to the cached one
+INSERT INTO posting (account_id, currency, amount, date) SELECT account_id, currency, balance, now() FROM balance WHERE balance <> 0;
@@ -0,0 +1,2 @@
+ALTER TABLE transaction ADD COLUMN kind VARCHAR(20) NOT NULL DEFAULT 'transfer';
+ALTER TABLE transaction ALTER COLUMN kind DROP DEFAULT;
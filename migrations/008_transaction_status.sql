@@ -0,0 +1,2 @@
+ALTER TABLE transaction ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'completed';
+ALTER TABLE transaction ALTER COLUMN status DROP DEFAULT;
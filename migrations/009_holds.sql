@@ -0,0 +1,2 @@
+CREATE TABLE hold (id SERIAL PRIMARY KEY, "from" BIGINT NOT NULL, "to" BIGINT NOT NULL, amount NUMERIC(20,2) NOT NULL, currency CHAR(3) NOT NULL, status VARCHAR(20) NOT NULL, transaction_id BIGINT, created_at TIMESTAMP NOT NULL, expires_at TIMESTAMP NOT NULL);
+CREATE INDEX hold_from_idx ON hold ("from", currency) WHERE status = 'active';
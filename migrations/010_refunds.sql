@@ -0,0 +1,2 @@
+ALTER TABLE transaction ADD COLUMN original_transaction_id BIGINT;
+CREATE INDEX transaction_original_transaction_id_idx ON transaction (original_transaction_id);
@@ -0,0 +1,2 @@
+CREATE INDEX transaction_from_date_idx ON transaction ("from", date, id);
+CREATE INDEX transaction_to_date_idx ON transaction ("to", date, id);
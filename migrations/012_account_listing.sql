@@ -0,0 +1,4 @@
+CREATE INDEX account_first_name_idx ON account (first_name, id);
+CREATE INDEX account_last_name_idx ON account (last_name, id);
+CREATE INDEX account_first_name_prefix_idx ON account (lower(first_name) text_pattern_ops);
+CREATE INDEX account_last_name_prefix_idx ON account (lower(last_name) text_pattern_ops);
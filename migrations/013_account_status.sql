@@ -0,0 +1 @@
+ALTER TABLE account ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active';
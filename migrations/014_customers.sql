@@ -0,0 +1,4 @@
+CREATE TABLE customer (id SERIAL PRIMARY KEY, first_name VARCHAR(50) NOT NULL, last_name VARCHAR(50) NOT NULL, email VARCHAR(254) NOT NULL, created_at TIMESTAMP NOT NULL);
+ALTER TABLE account ADD COLUMN customer_id BIGINT REFERENCES customer (id);
+CREATE INDEX account_customer_id_idx ON account (customer_id);
+ALTER TABLE transaction ADD COLUMN internal BOOLEAN NOT NULL DEFAULT false;
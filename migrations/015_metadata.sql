@@ -0,0 +1,4 @@
+ALTER TABLE account ADD COLUMN external_id VARCHAR(255) UNIQUE, ADD COLUMN metadata JSONB NOT NULL DEFAULT '{}';
+CREATE INDEX account_metadata_idx ON account USING GIN (metadata jsonb_path_ops);
+ALTER TABLE transaction ADD COLUMN external_id VARCHAR(255) UNIQUE, ADD COLUMN metadata JSONB NOT NULL DEFAULT '{}';
+CREATE INDEX transaction_metadata_idx ON transaction USING GIN (metadata jsonb_path_ops);
@@ -0,0 +1 @@
+CREATE TABLE api_key (id SERIAL PRIMARY KEY, name VARCHAR(100) NOT NULL, prefix CHAR(8) NOT NULL UNIQUE, hash CHAR(64) NOT NULL, scopes TEXT[] NOT NULL, created_at TIMESTAMP NOT NULL, revoked_at TIMESTAMP);
@@ -0,0 +1 @@
+ALTER TABLE account ADD COLUMN tier VARCHAR(20) NOT NULL DEFAULT 'standard';
@@ -0,0 +1 @@
+CREATE TABLE spending_limit (account_id BIGINT NOT NULL REFERENCES account (id), currency CHAR(3) NOT NULL, overdraft NUMERIC(20,2), min_balance NUMERIC(20,2), max_transaction NUMERIC(20,2), daily_outflow NUMERIC(20,2), monthly_outflow NUMERIC(20,2), PRIMARY KEY (account_id, currency));
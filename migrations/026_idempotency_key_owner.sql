@@ -0,0 +1,3 @@
+-- keys are scoped by the authenticated client, keys stored before belong to nobody and expire with retention
+ALTER TABLE idempotency_key ADD COLUMN owner VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE idempotency_key DROP CONSTRAINT idempotency_key_pkey, ADD PRIMARY KEY (key, operation, owner);
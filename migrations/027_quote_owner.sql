@@ -0,0 +1,2 @@
+-- quotes are bound to the authenticated client requested them, quotes stored before belong to nobody and expire
+ALTER TABLE quote ADD COLUMN owner VARCHAR(64) NOT NULL DEFAULT '';
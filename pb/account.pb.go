@@ -0,0 +1,627 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: account.proto
+
+package pb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Account struct {
+	Id         int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CustomerId int64  `protobuf:"varint,2,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	FirstName  string `protobuf:"bytes,3,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName   string `protobuf:"bytes,4,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Status     string `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	ExternalId string `protobuf:"bytes,6,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	// JSON object, empty when account has no metadata
+	Metadata             string   `protobuf:"bytes,7,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Tier                 string   `protobuf:"bytes,8,opt,name=tier,proto3" json:"tier,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Account) Reset()         { *m = Account{} }
+func (m *Account) String() string { return proto.CompactTextString(m) }
+func (*Account) ProtoMessage()    {}
+func (*Account) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e28828dcb8d24f0, []int{0}
+}
+
+func (m *Account) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Account.Unmarshal(m, b)
+}
+func (m *Account) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Account.Marshal(b, m, deterministic)
+}
+func (m *Account) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Account.Merge(m, src)
+}
+func (m *Account) XXX_Size() int {
+	return xxx_messageInfo_Account.Size(m)
+}
+func (m *Account) XXX_DiscardUnknown() {
+	xxx_messageInfo_Account.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Account proto.InternalMessageInfo
+
+func (m *Account) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Account) GetCustomerId() int64 {
+	if m != nil {
+		return m.CustomerId
+	}
+	return 0
+}
+
+func (m *Account) GetFirstName() string {
+	if m != nil {
+		return m.FirstName
+	}
+	return ""
+}
+
+func (m *Account) GetLastName() string {
+	if m != nil {
+		return m.LastName
+	}
+	return ""
+}
+
+func (m *Account) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *Account) GetExternalId() string {
+	if m != nil {
+		return m.ExternalId
+	}
+	return ""
+}
+
+func (m *Account) GetMetadata() string {
+	if m != nil {
+		return m.Metadata
+	}
+	return ""
+}
+
+func (m *Account) GetTier() string {
+	if m != nil {
+		return m.Tier
+	}
+	return ""
+}
+
+type AddAccountRequest struct {
+	CustomerId int64  `protobuf:"varint,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	FirstName  string `protobuf:"bytes,2,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName   string `protobuf:"bytes,3,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	ExternalId string `protobuf:"bytes,4,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	// JSON object
+	Metadata string `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// standard when not set
+	Tier                 string   `protobuf:"bytes,6,opt,name=tier,proto3" json:"tier,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddAccountRequest) Reset()         { *m = AddAccountRequest{} }
+func (m *AddAccountRequest) String() string { return proto.CompactTextString(m) }
+func (*AddAccountRequest) ProtoMessage()    {}
+func (*AddAccountRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e28828dcb8d24f0, []int{1}
+}
+
+func (m *AddAccountRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddAccountRequest.Unmarshal(m, b)
+}
+func (m *AddAccountRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddAccountRequest.Marshal(b, m, deterministic)
+}
+func (m *AddAccountRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddAccountRequest.Merge(m, src)
+}
+func (m *AddAccountRequest) XXX_Size() int {
+	return xxx_messageInfo_AddAccountRequest.Size(m)
+}
+func (m *AddAccountRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddAccountRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddAccountRequest proto.InternalMessageInfo
+
+func (m *AddAccountRequest) GetCustomerId() int64 {
+	if m != nil {
+		return m.CustomerId
+	}
+	return 0
+}
+
+func (m *AddAccountRequest) GetFirstName() string {
+	if m != nil {
+		return m.FirstName
+	}
+	return ""
+}
+
+func (m *AddAccountRequest) GetLastName() string {
+	if m != nil {
+		return m.LastName
+	}
+	return ""
+}
+
+func (m *AddAccountRequest) GetExternalId() string {
+	if m != nil {
+		return m.ExternalId
+	}
+	return ""
+}
+
+func (m *AddAccountRequest) GetMetadata() string {
+	if m != nil {
+		return m.Metadata
+	}
+	return ""
+}
+
+func (m *AddAccountRequest) GetTier() string {
+	if m != nil {
+		return m.Tier
+	}
+	return ""
+}
+
+type AccountRequest struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AccountRequest) Reset()         { *m = AccountRequest{} }
+func (m *AccountRequest) String() string { return proto.CompactTextString(m) }
+func (*AccountRequest) ProtoMessage()    {}
+func (*AccountRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e28828dcb8d24f0, []int{2}
+}
+
+func (m *AccountRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AccountRequest.Unmarshal(m, b)
+}
+func (m *AccountRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AccountRequest.Marshal(b, m, deterministic)
+}
+func (m *AccountRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountRequest.Merge(m, src)
+}
+func (m *AccountRequest) XXX_Size() int {
+	return xxx_messageInfo_AccountRequest.Size(m)
+}
+func (m *AccountRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccountRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AccountRequest proto.InternalMessageInfo
+
+func (m *AccountRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type CloseAccountRequest struct {
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// account receiving remaining balances, required when account has funds
+	SweepTo              int64    `protobuf:"varint,2,opt,name=sweep_to,json=sweepTo,proto3" json:"sweep_to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CloseAccountRequest) Reset()         { *m = CloseAccountRequest{} }
+func (m *CloseAccountRequest) String() string { return proto.CompactTextString(m) }
+func (*CloseAccountRequest) ProtoMessage()    {}
+func (*CloseAccountRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e28828dcb8d24f0, []int{3}
+}
+
+func (m *CloseAccountRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CloseAccountRequest.Unmarshal(m, b)
+}
+func (m *CloseAccountRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CloseAccountRequest.Marshal(b, m, deterministic)
+}
+func (m *CloseAccountRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CloseAccountRequest.Merge(m, src)
+}
+func (m *CloseAccountRequest) XXX_Size() int {
+	return xxx_messageInfo_CloseAccountRequest.Size(m)
+}
+func (m *CloseAccountRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CloseAccountRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CloseAccountRequest proto.InternalMessageInfo
+
+func (m *CloseAccountRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *CloseAccountRequest) GetSweepTo() int64 {
+	if m != nil {
+		return m.SweepTo
+	}
+	return 0
+}
+
+type AccountReply struct {
+	Account              *Account `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AccountReply) Reset()         { *m = AccountReply{} }
+func (m *AccountReply) String() string { return proto.CompactTextString(m) }
+func (*AccountReply) ProtoMessage()    {}
+func (*AccountReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e28828dcb8d24f0, []int{4}
+}
+
+func (m *AccountReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AccountReply.Unmarshal(m, b)
+}
+func (m *AccountReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AccountReply.Marshal(b, m, deterministic)
+}
+func (m *AccountReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountReply.Merge(m, src)
+}
+func (m *AccountReply) XXX_Size() int {
+	return xxx_messageInfo_AccountReply.Size(m)
+}
+func (m *AccountReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccountReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AccountReply proto.InternalMessageInfo
+
+func (m *AccountReply) GetAccount() *Account {
+	if m != nil {
+		return m.Account
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Account)(nil), "coins.Account")
+	proto.RegisterType((*AddAccountRequest)(nil), "coins.AddAccountRequest")
+	proto.RegisterType((*AccountRequest)(nil), "coins.AccountRequest")
+	proto.RegisterType((*CloseAccountRequest)(nil), "coins.CloseAccountRequest")
+	proto.RegisterType((*AccountReply)(nil), "coins.AccountReply")
+}
+
+func init() { proto.RegisterFile("account.proto", fileDescriptor_8e28828dcb8d24f0) }
+
+var fileDescriptor_8e28828dcb8d24f0 = []byte{
+	// 387 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x93, 0x4f, 0x6b, 0xdb, 0x30,
+	0x18, 0xc6, 0xb1, 0xe3, 0xd8, 0xce, 0x9b, 0x3f, 0x63, 0x0a, 0x1b, 0x5e, 0xc6, 0x58, 0xf0, 0x29,
+	0xa7, 0x1c, 0xb2, 0x4b, 0x20, 0x8c, 0x2d, 0x1b, 0xb4, 0xe4, 0xd2, 0x83, 0x69, 0x2f, 0xbd, 0x04,
+	0xc5, 0x7a, 0x03, 0x06, 0xdb, 0x72, 0x2d, 0x99, 0xfe, 0xf9, 0x76, 0xfd, 0x24, 0xfd, 0x20, 0xbd,
+	0x94, 0x28, 0x56, 0x1b, 0x44, 0xe3, 0x42, 0x6e, 0xd6, 0xf3, 0xbc, 0x8f, 0x5e, 0xfd, 0xf4, 0xca,
+	0xd0, 0xa7, 0x71, 0xcc, 0xab, 0x5c, 0x4e, 0x8b, 0x92, 0x4b, 0x4e, 0xda, 0x31, 0x4f, 0x72, 0x11,
+	0x3e, 0x59, 0xe0, 0x2d, 0xf7, 0x06, 0x19, 0x80, 0x9d, 0xb0, 0xc0, 0x1a, 0x5b, 0x93, 0x56, 0x64,
+	0x27, 0x8c, 0xfc, 0x84, 0x6e, 0x5c, 0x09, 0xc9, 0x33, 0x2c, 0xd7, 0x09, 0x0b, 0x6c, 0x65, 0x80,
+	0x96, 0x56, 0x8c, 0xfc, 0x00, 0xd8, 0x26, 0xa5, 0x90, 0xeb, 0x9c, 0x66, 0x18, 0xb4, 0xc6, 0xd6,
+	0xa4, 0x13, 0x75, 0x94, 0x72, 0x41, 0x33, 0x24, 0xdf, 0xa1, 0x93, 0x52, 0xed, 0x3a, 0xca, 0xf5,
+	0x53, 0x5a, 0x9b, 0x5f, 0xc1, 0x15, 0x92, 0xca, 0x4a, 0x04, 0x6d, 0xe5, 0xd4, 0xab, 0x5d, 0x53,
+	0xbc, 0x93, 0x58, 0xe6, 0x34, 0xdd, 0x35, 0x75, 0x95, 0x09, 0x5a, 0x5a, 0x31, 0x32, 0x02, 0x3f,
+	0x43, 0x49, 0x19, 0x95, 0x34, 0xf0, 0xf6, 0x9b, 0xea, 0x35, 0x21, 0xe0, 0xc8, 0x04, 0xcb, 0xc0,
+	0x57, 0xba, 0xfa, 0x0e, 0x1f, 0x2d, 0xf8, 0xbc, 0x64, 0xac, 0x86, 0x8c, 0xf0, 0xa6, 0x42, 0x21,
+	0x4d, 0x36, 0xeb, 0x03, 0x36, 0xbb, 0x91, 0xad, 0x65, 0xb0, 0x19, 0x0c, 0x4e, 0x23, 0x43, 0xfb,
+	0x08, 0x83, 0x7b, 0xc0, 0x30, 0x86, 0x81, 0x71, 0x7e, 0x63, 0x56, 0xe1, 0x5f, 0x18, 0xfe, 0x4f,
+	0xb9, 0xc0, 0xe6, 0x32, 0xf2, 0x0d, 0x7c, 0x71, 0x8b, 0x58, 0xac, 0x25, 0xaf, 0xe7, 0xe9, 0xa9,
+	0xf5, 0x25, 0x0f, 0xe7, 0xd0, 0x7b, 0x0d, 0x17, 0xe9, 0x3d, 0x99, 0x80, 0x57, 0xbf, 0x18, 0x95,
+	0xef, 0xce, 0x06, 0x53, 0xf5, 0x64, 0xa6, 0xba, 0x4a, 0xdb, 0xb3, 0x67, 0x1b, 0xfc, 0x5a, 0x14,
+	0x64, 0x01, 0xf0, 0x76, 0xdb, 0x24, 0xd0, 0x19, 0x73, 0x00, 0xa3, 0xa1, 0xb1, 0x9b, 0xea, 0x39,
+	0x07, 0x38, 0x47, 0xa9, 0xc3, 0x5f, 0xcc, 0x92, 0x86, 0xe4, 0x02, 0xfa, 0x67, 0x25, 0xe2, 0x03,
+	0x9e, 0x12, 0xfe, 0x0d, 0x9f, 0xae, 0xf2, 0xed, 0xc9, 0xf1, 0x3f, 0xd0, 0x3b, 0xbc, 0x7b, 0x32,
+	0xaa, 0x8b, 0xde, 0x19, 0xc8, 0xd1, 0xc3, 0x47, 0xc8, 0x0b, 0xcc, 0x4f, 0xe8, 0xfe, 0xcf, 0xb9,
+	0xb6, 0x8b, 0xcd, 0xc6, 0x55, 0x7f, 0xf5, 0xaf, 0x97, 0x01, 0x00, 0x60, 0x5e, 0xee, 0xe0, 0xe6,
+	0x03, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// AccountsClient is the client API for Accounts service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AccountsClient interface {
+	AddAccount(ctx context.Context, in *AddAccountRequest, opts ...grpc.CallOption) (*AccountReply, error)
+	GetAccount(ctx context.Context, in *AccountRequest, opts ...grpc.CallOption) (*AccountReply, error)
+	FreezeAccount(ctx context.Context, in *AccountRequest, opts ...grpc.CallOption) (*AccountReply, error)
+	UnfreezeAccount(ctx context.Context, in *AccountRequest, opts ...grpc.CallOption) (*AccountReply, error)
+	CloseAccount(ctx context.Context, in *CloseAccountRequest, opts ...grpc.CallOption) (*AccountReply, error)
+	ReopenAccount(ctx context.Context, in *AccountRequest, opts ...grpc.CallOption) (*AccountReply, error)
+}
+
+type accountsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAccountsClient(cc *grpc.ClientConn) AccountsClient {
+	return &accountsClient{cc}
+}
+
+func (c *accountsClient) AddAccount(ctx context.Context, in *AddAccountRequest, opts ...grpc.CallOption) (*AccountReply, error) {
+	out := new(AccountReply)
+	err := c.cc.Invoke(ctx, "/coins.Accounts/AddAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) GetAccount(ctx context.Context, in *AccountRequest, opts ...grpc.CallOption) (*AccountReply, error) {
+	out := new(AccountReply)
+	err := c.cc.Invoke(ctx, "/coins.Accounts/GetAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) FreezeAccount(ctx context.Context, in *AccountRequest, opts ...grpc.CallOption) (*AccountReply, error) {
+	out := new(AccountReply)
+	err := c.cc.Invoke(ctx, "/coins.Accounts/FreezeAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) UnfreezeAccount(ctx context.Context, in *AccountRequest, opts ...grpc.CallOption) (*AccountReply, error) {
+	out := new(AccountReply)
+	err := c.cc.Invoke(ctx, "/coins.Accounts/UnfreezeAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) CloseAccount(ctx context.Context, in *CloseAccountRequest, opts ...grpc.CallOption) (*AccountReply, error) {
+	out := new(AccountReply)
+	err := c.cc.Invoke(ctx, "/coins.Accounts/CloseAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) ReopenAccount(ctx context.Context, in *AccountRequest, opts ...grpc.CallOption) (*AccountReply, error) {
+	out := new(AccountReply)
+	err := c.cc.Invoke(ctx, "/coins.Accounts/ReopenAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AccountsServer is the server API for Accounts service.
+type AccountsServer interface {
+	AddAccount(context.Context, *AddAccountRequest) (*AccountReply, error)
+	GetAccount(context.Context, *AccountRequest) (*AccountReply, error)
+	FreezeAccount(context.Context, *AccountRequest) (*AccountReply, error)
+	UnfreezeAccount(context.Context, *AccountRequest) (*AccountReply, error)
+	CloseAccount(context.Context, *CloseAccountRequest) (*AccountReply, error)
+	ReopenAccount(context.Context, *AccountRequest) (*AccountReply, error)
+}
+
+// UnimplementedAccountsServer can be embedded to have forward compatible implementations.
+type UnimplementedAccountsServer struct {
+}
+
+func (*UnimplementedAccountsServer) AddAccount(ctx context.Context, req *AddAccountRequest) (*AccountReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddAccount not implemented")
+}
+func (*UnimplementedAccountsServer) GetAccount(ctx context.Context, req *AccountRequest) (*AccountReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccount not implemented")
+}
+func (*UnimplementedAccountsServer) FreezeAccount(ctx context.Context, req *AccountRequest) (*AccountReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FreezeAccount not implemented")
+}
+func (*UnimplementedAccountsServer) UnfreezeAccount(ctx context.Context, req *AccountRequest) (*AccountReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnfreezeAccount not implemented")
+}
+func (*UnimplementedAccountsServer) CloseAccount(ctx context.Context, req *CloseAccountRequest) (*AccountReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseAccount not implemented")
+}
+func (*UnimplementedAccountsServer) ReopenAccount(ctx context.Context, req *AccountRequest) (*AccountReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReopenAccount not implemented")
+}
+
+func RegisterAccountsServer(s *grpc.Server, srv AccountsServer) {
+	s.RegisterService(&_Accounts_serviceDesc, srv)
+}
+
+func _Accounts_AddAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).AddAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/coins.Accounts/AddAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).AddAccount(ctx, req.(*AddAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_GetAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).GetAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/coins.Accounts/GetAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).GetAccount(ctx, req.(*AccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_FreezeAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).FreezeAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/coins.Accounts/FreezeAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).FreezeAccount(ctx, req.(*AccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_UnfreezeAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).UnfreezeAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/coins.Accounts/UnfreezeAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).UnfreezeAccount(ctx, req.(*AccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_CloseAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).CloseAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/coins.Accounts/CloseAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).CloseAccount(ctx, req.(*CloseAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_ReopenAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).ReopenAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/coins.Accounts/ReopenAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).ReopenAccount(ctx, req.(*AccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Accounts_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "coins.Accounts",
+	HandlerType: (*AccountsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddAccount",
+			Handler:    _Accounts_AddAccount_Handler,
+		},
+		{
+			MethodName: "GetAccount",
+			Handler:    _Accounts_GetAccount_Handler,
+		},
+		{
+			MethodName: "FreezeAccount",
+			Handler:    _Accounts_FreezeAccount_Handler,
+		},
+		{
+			MethodName: "UnfreezeAccount",
+			Handler:    _Accounts_UnfreezeAccount_Handler,
+		},
+		{
+			MethodName: "CloseAccount",
+			Handler:    _Accounts_CloseAccount_Handler,
+		},
+		{
+			MethodName: "ReopenAccount",
+			Handler:    _Accounts_ReopenAccount_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "account.proto",
+}
@@ -0,0 +1,1159 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: payment.proto
+
+package pb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Balance struct {
+	AccountId            int64    `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Currency             string   `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Ledger               string   `protobuf:"bytes,3,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	Available            string   `protobuf:"bytes,4,opt,name=available,proto3" json:"available,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Balance) Reset()         { *m = Balance{} }
+func (m *Balance) String() string { return proto.CompactTextString(m) }
+func (*Balance) ProtoMessage()    {}
+func (*Balance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6362648dfa63d410, []int{0}
+}
+
+func (m *Balance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Balance.Unmarshal(m, b)
+}
+func (m *Balance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Balance.Marshal(b, m, deterministic)
+}
+func (m *Balance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Balance.Merge(m, src)
+}
+func (m *Balance) XXX_Size() int {
+	return xxx_messageInfo_Balance.Size(m)
+}
+func (m *Balance) XXX_DiscardUnknown() {
+	xxx_messageInfo_Balance.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Balance proto.InternalMessageInfo
+
+func (m *Balance) GetAccountId() int64 {
+	if m != nil {
+		return m.AccountId
+	}
+	return 0
+}
+
+func (m *Balance) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *Balance) GetLedger() string {
+	if m != nil {
+		return m.Ledger
+	}
+	return ""
+}
+
+func (m *Balance) GetAvailable() string {
+	if m != nil {
+		return m.Available
+	}
+	return ""
+}
+
+type Transaction struct {
+	Id                    int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind                  string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Status                string `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	From                  int64  `protobuf:"varint,4,opt,name=from,proto3" json:"from,omitempty"`
+	To                    int64  `protobuf:"varint,5,opt,name=to,proto3" json:"to,omitempty"`
+	Amount                string `protobuf:"bytes,6,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency              string `protobuf:"bytes,7,opt,name=currency,proto3" json:"currency,omitempty"`
+	ToAmount              string `protobuf:"bytes,8,opt,name=to_amount,json=toAmount,proto3" json:"to_amount,omitempty"`
+	ToCurrency            string `protobuf:"bytes,9,opt,name=to_currency,json=toCurrency,proto3" json:"to_currency,omitempty"`
+	Rate                  string `protobuf:"bytes,10,opt,name=rate,proto3" json:"rate,omitempty"`
+	QuoteId               int64  `protobuf:"varint,11,opt,name=quote_id,json=quoteId,proto3" json:"quote_id,omitempty"`
+	OriginalTransactionId int64  `protobuf:"varint,12,opt,name=original_transaction_id,json=originalTransactionId,proto3" json:"original_transaction_id,omitempty"`
+	Internal              bool   `protobuf:"varint,13,opt,name=internal,proto3" json:"internal,omitempty"`
+	ExternalId            string `protobuf:"bytes,14,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	// JSON object, empty when transaction has no metadata
+	Metadata             string               `protobuf:"bytes,15,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Date                 *timestamp.Timestamp `protobuf:"bytes,16,opt,name=date,proto3" json:"date,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Transaction) Reset()         { *m = Transaction{} }
+func (m *Transaction) String() string { return proto.CompactTextString(m) }
+func (*Transaction) ProtoMessage()    {}
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6362648dfa63d410, []int{1}
+}
+
+func (m *Transaction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Transaction.Unmarshal(m, b)
+}
+func (m *Transaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Transaction.Marshal(b, m, deterministic)
+}
+func (m *Transaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Transaction.Merge(m, src)
+}
+func (m *Transaction) XXX_Size() int {
+	return xxx_messageInfo_Transaction.Size(m)
+}
+func (m *Transaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_Transaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Transaction proto.InternalMessageInfo
+
+func (m *Transaction) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Transaction) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *Transaction) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *Transaction) GetFrom() int64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *Transaction) GetTo() int64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+func (m *Transaction) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *Transaction) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *Transaction) GetToAmount() string {
+	if m != nil {
+		return m.ToAmount
+	}
+	return ""
+}
+
+func (m *Transaction) GetToCurrency() string {
+	if m != nil {
+		return m.ToCurrency
+	}
+	return ""
+}
+
+func (m *Transaction) GetRate() string {
+	if m != nil {
+		return m.Rate
+	}
+	return ""
+}
+
+func (m *Transaction) GetQuoteId() int64 {
+	if m != nil {
+		return m.QuoteId
+	}
+	return 0
+}
+
+func (m *Transaction) GetOriginalTransactionId() int64 {
+	if m != nil {
+		return m.OriginalTransactionId
+	}
+	return 0
+}
+
+func (m *Transaction) GetInternal() bool {
+	if m != nil {
+		return m.Internal
+	}
+	return false
+}
+
+func (m *Transaction) GetExternalId() string {
+	if m != nil {
+		return m.ExternalId
+	}
+	return ""
+}
+
+func (m *Transaction) GetMetadata() string {
+	if m != nil {
+		return m.Metadata
+	}
+	return ""
+}
+
+func (m *Transaction) GetDate() *timestamp.Timestamp {
+	if m != nil {
+		return m.Date
+	}
+	return nil
+}
+
+type BalanceRequest struct {
+	AccountId            int64    `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BalanceRequest) Reset()         { *m = BalanceRequest{} }
+func (m *BalanceRequest) String() string { return proto.CompactTextString(m) }
+func (*BalanceRequest) ProtoMessage()    {}
+func (*BalanceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6362648dfa63d410, []int{2}
+}
+
+func (m *BalanceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BalanceRequest.Unmarshal(m, b)
+}
+func (m *BalanceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BalanceRequest.Marshal(b, m, deterministic)
+}
+func (m *BalanceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BalanceRequest.Merge(m, src)
+}
+func (m *BalanceRequest) XXX_Size() int {
+	return xxx_messageInfo_BalanceRequest.Size(m)
+}
+func (m *BalanceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BalanceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BalanceRequest proto.InternalMessageInfo
+
+func (m *BalanceRequest) GetAccountId() int64 {
+	if m != nil {
+		return m.AccountId
+	}
+	return 0
+}
+
+type BalanceReply struct {
+	Balances             []*Balance `protobuf:"bytes,1,rep,name=balances,proto3" json:"balances,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *BalanceReply) Reset()         { *m = BalanceReply{} }
+func (m *BalanceReply) String() string { return proto.CompactTextString(m) }
+func (*BalanceReply) ProtoMessage()    {}
+func (*BalanceReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6362648dfa63d410, []int{3}
+}
+
+func (m *BalanceReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BalanceReply.Unmarshal(m, b)
+}
+func (m *BalanceReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BalanceReply.Marshal(b, m, deterministic)
+}
+func (m *BalanceReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BalanceReply.Merge(m, src)
+}
+func (m *BalanceReply) XXX_Size() int {
+	return xxx_messageInfo_BalanceReply.Size(m)
+}
+func (m *BalanceReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_BalanceReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BalanceReply proto.InternalMessageInfo
+
+func (m *BalanceReply) GetBalances() []*Balance {
+	if m != nil {
+		return m.Balances
+	}
+	return nil
+}
+
+type ListTransactionsRequest struct {
+	AccountId int64  `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Cursor    string `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit     int32  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// asc or desc
+	Order                string   `protobuf:"bytes,4,opt,name=order,proto3" json:"order,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListTransactionsRequest) Reset()         { *m = ListTransactionsRequest{} }
+func (m *ListTransactionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListTransactionsRequest) ProtoMessage()    {}
+func (*ListTransactionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6362648dfa63d410, []int{4}
+}
+
+func (m *ListTransactionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTransactionsRequest.Unmarshal(m, b)
+}
+func (m *ListTransactionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTransactionsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListTransactionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTransactionsRequest.Merge(m, src)
+}
+func (m *ListTransactionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListTransactionsRequest.Size(m)
+}
+func (m *ListTransactionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTransactionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTransactionsRequest proto.InternalMessageInfo
+
+func (m *ListTransactionsRequest) GetAccountId() int64 {
+	if m != nil {
+		return m.AccountId
+	}
+	return 0
+}
+
+func (m *ListTransactionsRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *ListTransactionsRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *ListTransactionsRequest) GetOrder() string {
+	if m != nil {
+		return m.Order
+	}
+	return ""
+}
+
+type ListTransactionsReply struct {
+	Transactions         []*Transaction `protobuf:"bytes,1,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	NextCursor           string         `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ListTransactionsReply) Reset()         { *m = ListTransactionsReply{} }
+func (m *ListTransactionsReply) String() string { return proto.CompactTextString(m) }
+func (*ListTransactionsReply) ProtoMessage()    {}
+func (*ListTransactionsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6362648dfa63d410, []int{5}
+}
+
+func (m *ListTransactionsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTransactionsReply.Unmarshal(m, b)
+}
+func (m *ListTransactionsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTransactionsReply.Marshal(b, m, deterministic)
+}
+func (m *ListTransactionsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTransactionsReply.Merge(m, src)
+}
+func (m *ListTransactionsReply) XXX_Size() int {
+	return xxx_messageInfo_ListTransactionsReply.Size(m)
+}
+func (m *ListTransactionsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTransactionsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTransactionsReply proto.InternalMessageInfo
+
+func (m *ListTransactionsReply) GetTransactions() []*Transaction {
+	if m != nil {
+		return m.Transactions
+	}
+	return nil
+}
+
+func (m *ListTransactionsReply) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+type TransferRequest struct {
+	From       int64  `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To         int64  `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	Amount     string `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency   string `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	ToCurrency string `protobuf:"bytes,5,opt,name=to_currency,json=toCurrency,proto3" json:"to_currency,omitempty"`
+	Convert    bool   `protobuf:"varint,6,opt,name=convert,proto3" json:"convert,omitempty"`
+	QuoteId    int64  `protobuf:"varint,7,opt,name=quote_id,json=quoteId,proto3" json:"quote_id,omitempty"`
+	ExternalId string `protobuf:"bytes,8,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	// JSON object
+	Metadata             string   `protobuf:"bytes,9,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	IdempotencyKey       string   `protobuf:"bytes,10,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransferRequest) Reset()         { *m = TransferRequest{} }
+func (m *TransferRequest) String() string { return proto.CompactTextString(m) }
+func (*TransferRequest) ProtoMessage()    {}
+func (*TransferRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6362648dfa63d410, []int{6}
+}
+
+func (m *TransferRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferRequest.Unmarshal(m, b)
+}
+func (m *TransferRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferRequest.Marshal(b, m, deterministic)
+}
+func (m *TransferRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferRequest.Merge(m, src)
+}
+func (m *TransferRequest) XXX_Size() int {
+	return xxx_messageInfo_TransferRequest.Size(m)
+}
+func (m *TransferRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferRequest proto.InternalMessageInfo
+
+func (m *TransferRequest) GetFrom() int64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *TransferRequest) GetTo() int64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+func (m *TransferRequest) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *TransferRequest) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *TransferRequest) GetToCurrency() string {
+	if m != nil {
+		return m.ToCurrency
+	}
+	return ""
+}
+
+func (m *TransferRequest) GetConvert() bool {
+	if m != nil {
+		return m.Convert
+	}
+	return false
+}
+
+func (m *TransferRequest) GetQuoteId() int64 {
+	if m != nil {
+		return m.QuoteId
+	}
+	return 0
+}
+
+func (m *TransferRequest) GetExternalId() string {
+	if m != nil {
+		return m.ExternalId
+	}
+	return ""
+}
+
+func (m *TransferRequest) GetMetadata() string {
+	if m != nil {
+		return m.Metadata
+	}
+	return ""
+}
+
+func (m *TransferRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
+type TopUpRequest struct {
+	AccountId  int64  `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Amount     string `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency   string `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	ExternalId string `protobuf:"bytes,4,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	// JSON object
+	Metadata             string   `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	IdempotencyKey       string   `protobuf:"bytes,6,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TopUpRequest) Reset()         { *m = TopUpRequest{} }
+func (m *TopUpRequest) String() string { return proto.CompactTextString(m) }
+func (*TopUpRequest) ProtoMessage()    {}
+func (*TopUpRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6362648dfa63d410, []int{7}
+}
+
+func (m *TopUpRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopUpRequest.Unmarshal(m, b)
+}
+func (m *TopUpRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopUpRequest.Marshal(b, m, deterministic)
+}
+func (m *TopUpRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopUpRequest.Merge(m, src)
+}
+func (m *TopUpRequest) XXX_Size() int {
+	return xxx_messageInfo_TopUpRequest.Size(m)
+}
+func (m *TopUpRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopUpRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopUpRequest proto.InternalMessageInfo
+
+func (m *TopUpRequest) GetAccountId() int64 {
+	if m != nil {
+		return m.AccountId
+	}
+	return 0
+}
+
+func (m *TopUpRequest) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *TopUpRequest) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *TopUpRequest) GetExternalId() string {
+	if m != nil {
+		return m.ExternalId
+	}
+	return ""
+}
+
+func (m *TopUpRequest) GetMetadata() string {
+	if m != nil {
+		return m.Metadata
+	}
+	return ""
+}
+
+func (m *TopUpRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
+type TopUpReply struct {
+	Balance              *Balance `protobuf:"bytes,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TopUpReply) Reset()         { *m = TopUpReply{} }
+func (m *TopUpReply) String() string { return proto.CompactTextString(m) }
+func (*TopUpReply) ProtoMessage()    {}
+func (*TopUpReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6362648dfa63d410, []int{8}
+}
+
+func (m *TopUpReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopUpReply.Unmarshal(m, b)
+}
+func (m *TopUpReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopUpReply.Marshal(b, m, deterministic)
+}
+func (m *TopUpReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopUpReply.Merge(m, src)
+}
+func (m *TopUpReply) XXX_Size() int {
+	return xxx_messageInfo_TopUpReply.Size(m)
+}
+func (m *TopUpReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopUpReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopUpReply proto.InternalMessageInfo
+
+func (m *TopUpReply) GetBalance() *Balance {
+	if m != nil {
+		return m.Balance
+	}
+	return nil
+}
+
+type WithdrawRequest struct {
+	AccountId            int64    `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Amount               string   `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency             string   `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	IdempotencyKey       string   `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WithdrawRequest) Reset()         { *m = WithdrawRequest{} }
+func (m *WithdrawRequest) String() string { return proto.CompactTextString(m) }
+func (*WithdrawRequest) ProtoMessage()    {}
+func (*WithdrawRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6362648dfa63d410, []int{9}
+}
+
+func (m *WithdrawRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WithdrawRequest.Unmarshal(m, b)
+}
+func (m *WithdrawRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WithdrawRequest.Marshal(b, m, deterministic)
+}
+func (m *WithdrawRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WithdrawRequest.Merge(m, src)
+}
+func (m *WithdrawRequest) XXX_Size() int {
+	return xxx_messageInfo_WithdrawRequest.Size(m)
+}
+func (m *WithdrawRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WithdrawRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WithdrawRequest proto.InternalMessageInfo
+
+func (m *WithdrawRequest) GetAccountId() int64 {
+	if m != nil {
+		return m.AccountId
+	}
+	return 0
+}
+
+func (m *WithdrawRequest) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *WithdrawRequest) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *WithdrawRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
+type RefundRequest struct {
+	TransactionId int64 `protobuf:"varint,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	// full remaining amount when empty
+	Amount               string   `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	IdempotencyKey       string   `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RefundRequest) Reset()         { *m = RefundRequest{} }
+func (m *RefundRequest) String() string { return proto.CompactTextString(m) }
+func (*RefundRequest) ProtoMessage()    {}
+func (*RefundRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6362648dfa63d410, []int{10}
+}
+
+func (m *RefundRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RefundRequest.Unmarshal(m, b)
+}
+func (m *RefundRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RefundRequest.Marshal(b, m, deterministic)
+}
+func (m *RefundRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RefundRequest.Merge(m, src)
+}
+func (m *RefundRequest) XXX_Size() int {
+	return xxx_messageInfo_RefundRequest.Size(m)
+}
+func (m *RefundRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RefundRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RefundRequest proto.InternalMessageInfo
+
+func (m *RefundRequest) GetTransactionId() int64 {
+	if m != nil {
+		return m.TransactionId
+	}
+	return 0
+}
+
+func (m *RefundRequest) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *RefundRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
+type TransactionReply struct {
+	Transaction          *Transaction `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *TransactionReply) Reset()         { *m = TransactionReply{} }
+func (m *TransactionReply) String() string { return proto.CompactTextString(m) }
+func (*TransactionReply) ProtoMessage()    {}
+func (*TransactionReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6362648dfa63d410, []int{11}
+}
+
+func (m *TransactionReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransactionReply.Unmarshal(m, b)
+}
+func (m *TransactionReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransactionReply.Marshal(b, m, deterministic)
+}
+func (m *TransactionReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionReply.Merge(m, src)
+}
+func (m *TransactionReply) XXX_Size() int {
+	return xxx_messageInfo_TransactionReply.Size(m)
+}
+func (m *TransactionReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransactionReply proto.InternalMessageInfo
+
+func (m *TransactionReply) GetTransaction() *Transaction {
+	if m != nil {
+		return m.Transaction
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Balance)(nil), "coins.Balance")
+	proto.RegisterType((*Transaction)(nil), "coins.Transaction")
+	proto.RegisterType((*BalanceRequest)(nil), "coins.BalanceRequest")
+	proto.RegisterType((*BalanceReply)(nil), "coins.BalanceReply")
+	proto.RegisterType((*ListTransactionsRequest)(nil), "coins.ListTransactionsRequest")
+	proto.RegisterType((*ListTransactionsReply)(nil), "coins.ListTransactionsReply")
+	proto.RegisterType((*TransferRequest)(nil), "coins.TransferRequest")
+	proto.RegisterType((*TopUpRequest)(nil), "coins.TopUpRequest")
+	proto.RegisterType((*TopUpReply)(nil), "coins.TopUpReply")
+	proto.RegisterType((*WithdrawRequest)(nil), "coins.WithdrawRequest")
+	proto.RegisterType((*RefundRequest)(nil), "coins.RefundRequest")
+	proto.RegisterType((*TransactionReply)(nil), "coins.TransactionReply")
+}
+
+func init() { proto.RegisterFile("payment.proto", fileDescriptor_6362648dfa63d410) }
+
+var fileDescriptor_6362648dfa63d410 = []byte{
+	// 816 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x95, 0xcd, 0xae, 0xdb, 0x44,
+	0x14, 0xc7, 0xe5, 0x8f, 0x24, 0xce, 0xc9, 0xd7, 0x65, 0xe8, 0xed, 0x35, 0xa1, 0xd0, 0xc8, 0x12,
+	0x22, 0x62, 0x91, 0x48, 0x17, 0x74, 0x41, 0xb0, 0xa2, 0x5d, 0x40, 0x05, 0x8b, 0xca, 0xba, 0x08,
+	0x89, 0x4d, 0x34, 0xb1, 0x27, 0xc1, 0xaa, 0xed, 0x71, 0xc7, 0xe3, 0xb6, 0x41, 0xbc, 0x02, 0x8f,
+	0xc1, 0xb3, 0xb0, 0xe4, 0x7d, 0x58, 0xa1, 0xf9, 0xca, 0xb5, 0x1d, 0xa7, 0xbd, 0x9b, 0xee, 0x7c,
+	0xbe, 0x7c, 0xce, 0xff, 0xf8, 0x37, 0x63, 0x98, 0x14, 0xf8, 0x90, 0x91, 0x9c, 0xaf, 0x0a, 0x46,
+	0x39, 0x45, 0xbd, 0x88, 0x26, 0x79, 0x39, 0x7f, 0xbc, 0xa7, 0x74, 0x9f, 0x92, 0xb5, 0x74, 0x6e,
+	0xab, 0xdd, 0x9a, 0x27, 0x19, 0x29, 0x39, 0xce, 0x0a, 0x95, 0x17, 0xfc, 0x01, 0x83, 0x27, 0x38,
+	0xc5, 0x79, 0x44, 0xd0, 0x27, 0x00, 0x38, 0x8a, 0x68, 0x95, 0xf3, 0x4d, 0x12, 0xfb, 0xd6, 0xc2,
+	0x5a, 0x3a, 0xe1, 0x50, 0x7b, 0x9e, 0xc5, 0x68, 0x0e, 0x5e, 0x54, 0x31, 0x46, 0xf2, 0xe8, 0xe0,
+	0xdb, 0x0b, 0x6b, 0x39, 0x0c, 0x8f, 0x36, 0x7a, 0x08, 0xfd, 0x94, 0xc4, 0x7b, 0xc2, 0x7c, 0x47,
+	0x46, 0xb4, 0x85, 0x1e, 0xc1, 0x10, 0xbf, 0xc2, 0x49, 0x8a, 0xb7, 0x29, 0xf1, 0x5d, 0x19, 0xba,
+	0x73, 0x04, 0xff, 0x3a, 0x30, 0xba, 0x65, 0x38, 0x2f, 0x71, 0xc4, 0x13, 0x9a, 0xa3, 0x29, 0xd8,
+	0xc7, 0xc6, 0x76, 0x12, 0x23, 0x04, 0xee, 0x8b, 0x24, 0x8f, 0x75, 0x37, 0xf9, 0x2c, 0x3a, 0x95,
+	0x1c, 0xf3, 0xaa, 0x34, 0x9d, 0x94, 0x25, 0x72, 0x77, 0x8c, 0x66, 0xb2, 0x89, 0x13, 0xca, 0x67,
+	0xf1, 0x3e, 0x4e, 0xfd, 0x9e, 0x7a, 0x1f, 0xa7, 0xa2, 0x16, 0x67, 0x42, 0x8d, 0xdf, 0x57, 0xb5,
+	0xca, 0x6a, 0x28, 0x1b, 0xb4, 0x94, 0x7d, 0x0c, 0x43, 0x4e, 0x37, 0xba, 0xcc, 0x53, 0x41, 0x4e,
+	0xbf, 0x57, 0x85, 0x8f, 0x61, 0xc4, 0xe9, 0xe6, 0x58, 0x3b, 0x94, 0x61, 0xe0, 0xf4, 0xa9, 0xa9,
+	0x46, 0xe0, 0x32, 0xcc, 0x89, 0x0f, 0x4a, 0x81, 0x78, 0x46, 0x1f, 0x81, 0xf7, 0xb2, 0xa2, 0x9c,
+	0x88, 0x25, 0x8f, 0xe4, 0x6c, 0x03, 0x69, 0x3f, 0x8b, 0xd1, 0x0d, 0x5c, 0x51, 0x96, 0xec, 0x93,
+	0x1c, 0xa7, 0x1b, 0x7e, 0xb7, 0x18, 0x91, 0x39, 0x96, 0x99, 0x97, 0x26, 0x5c, 0x5b, 0x9b, 0xfa,
+	0x34, 0x49, 0xce, 0x09, 0xcb, 0x71, 0xea, 0x4f, 0x16, 0xd6, 0xd2, 0x0b, 0x8f, 0xb6, 0x98, 0x91,
+	0xbc, 0x51, 0xcf, 0xe2, 0x3d, 0x53, 0x35, 0xa3, 0x71, 0xa9, 0xe2, 0x8c, 0x70, 0x1c, 0x63, 0x8e,
+	0xfd, 0x99, 0x12, 0x68, 0x6c, 0xb4, 0x02, 0x37, 0x16, 0xf3, 0x5f, 0x2c, 0xac, 0xe5, 0xe8, 0x7a,
+	0xbe, 0x52, 0x34, 0xad, 0x0c, 0x4d, 0xab, 0x5b, 0x43, 0x53, 0x28, 0xf3, 0x82, 0x35, 0x4c, 0x35,
+	0x4d, 0x21, 0x79, 0x59, 0x91, 0x92, 0xbf, 0x03, 0xaa, 0xe0, 0x5b, 0x18, 0x1f, 0x0b, 0x8a, 0xf4,
+	0x80, 0xbe, 0x00, 0x6f, 0xab, 0xec, 0xd2, 0xb7, 0x16, 0xce, 0x72, 0x74, 0x3d, 0x5d, 0x49, 0x92,
+	0x57, 0x26, 0xed, 0x18, 0x0f, 0xfe, 0x84, 0xab, 0x9f, 0x93, 0x92, 0xd7, 0x56, 0x51, 0xde, 0xaf,
+	0xab, 0x00, 0x21, 0xaa, 0x58, 0x49, 0x99, 0x46, 0x4b, 0x5b, 0xe8, 0x01, 0xf4, 0xd2, 0x24, 0x4b,
+	0xb8, 0x64, 0xab, 0x17, 0x2a, 0x43, 0x78, 0x29, 0x8b, 0x09, 0xd3, 0x00, 0x2b, 0x23, 0x28, 0xe0,
+	0xf2, 0xb4, 0xbb, 0x90, 0x70, 0x03, 0xe3, 0xda, 0xb7, 0x33, 0x32, 0x90, 0x96, 0x51, 0xcb, 0x0f,
+	0x1b, 0x79, 0xe2, 0x43, 0xe5, 0xe4, 0x0d, 0xdf, 0x34, 0x26, 0x03, 0xe1, 0x7a, 0x2a, 0x3d, 0xc1,
+	0xdf, 0x36, 0xcc, 0x64, 0xf9, 0x8e, 0x30, 0x23, 0xd4, 0x60, 0x6f, 0x9d, 0x60, 0x6f, 0x77, 0x60,
+	0xef, 0x9c, 0xc5, 0xde, 0x6d, 0x61, 0xdf, 0x22, 0xbb, 0x77, 0x42, 0xb6, 0x0f, 0x83, 0x88, 0xe6,
+	0xaf, 0x08, 0x53, 0x87, 0xc9, 0x0b, 0x8d, 0xd9, 0xe0, 0x7b, 0xd0, 0xe4, 0xbb, 0xc5, 0xa2, 0xf7,
+	0x56, 0x16, 0x87, 0x2d, 0x16, 0x3f, 0x87, 0x59, 0x12, 0x93, 0xac, 0xa0, 0x5c, 0x0c, 0xb0, 0x79,
+	0x41, 0x0e, 0xfa, 0x58, 0x4d, 0x6b, 0xee, 0x9f, 0xc8, 0x21, 0xf8, 0xc7, 0x82, 0xf1, 0x2d, 0x2d,
+	0x7e, 0x29, 0xee, 0x4f, 0x83, 0xde, 0x8f, 0x7d, 0x76, 0x3f, 0xce, 0xe9, 0x7e, 0xea, 0x4a, 0xdc,
+	0xb7, 0x2a, 0xe9, 0xbd, 0x5b, 0x49, 0xbf, 0x53, 0xc9, 0x0d, 0x80, 0x16, 0x22, 0xc0, 0x5a, 0xc2,
+	0x40, 0xb3, 0x2f, 0x35, 0x9c, 0x1e, 0x0d, 0x13, 0x0e, 0xfe, 0xb2, 0x60, 0xf6, 0x6b, 0xc2, 0x7f,
+	0x8f, 0x19, 0x7e, 0xfd, 0x1e, 0x97, 0xd0, 0xa1, 0xc3, 0xed, 0xd4, 0xf1, 0x1a, 0x26, 0x21, 0xd9,
+	0x55, 0x79, 0x6c, 0x86, 0xf9, 0x0c, 0xa6, 0xad, 0xfb, 0x4d, 0x0d, 0x34, 0xe1, 0x8d, 0x7b, 0xed,
+	0xdc, 0x50, 0x1d, 0x8d, 0x9d, 0xce, 0xc6, 0x3f, 0xc2, 0x45, 0xfd, 0xc0, 0xc9, 0x35, 0x7e, 0x05,
+	0xa3, 0x5a, 0x17, 0xbd, 0xca, 0xae, 0xe3, 0x59, 0x4f, 0xbb, 0xfe, 0xcf, 0x06, 0xef, 0xb9, 0xfa,
+	0xc3, 0x96, 0xe8, 0x1b, 0x80, 0x1f, 0x08, 0x37, 0xff, 0xcd, 0xcb, 0xd6, 0x67, 0x50, 0x1a, 0xe7,
+	0x1f, 0xb6, 0xdd, 0xa2, 0xf9, 0x73, 0xb8, 0x68, 0xdf, 0x1a, 0xe8, 0x53, 0x9d, 0x78, 0xe6, 0x32,
+	0x9b, 0x3f, 0x3a, 0x1b, 0x17, 0x6f, 0xfc, 0x0e, 0x3c, 0x73, 0x29, 0xa0, 0x87, 0x75, 0x15, 0x77,
+	0xb7, 0xc4, 0xfc, 0xaa, 0x43, 0x9d, 0x2c, 0x5e, 0x43, 0x4f, 0x02, 0x86, 0xcc, 0xb0, 0xf5, 0x73,
+	0x33, 0xff, 0xa0, 0xe9, 0xd4, 0xdd, 0x0c, 0x58, 0xc7, 0x6e, 0x2d, 0xd2, 0xce, 0x77, 0xfb, 0x1a,
+	0xfa, 0x0a, 0x03, 0xf4, 0x40, 0xa7, 0x34, 0xa8, 0x38, 0x5b, 0xf8, 0xc4, 0xfd, 0xcd, 0x2e, 0xb6,
+	0xdb, 0xbe, 0xfc, 0xed, 0x7c, 0xf9, 0xff, 0x00, 0x10, 0xbf, 0xb7, 0xe1, 0xea, 0x08, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// PaymentsClient is the client API for Payments service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PaymentsClient interface {
+	GetBalance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceReply, error)
+	ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsReply, error)
+	Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransactionReply, error)
+	TopUp(ctx context.Context, in *TopUpRequest, opts ...grpc.CallOption) (*TopUpReply, error)
+	Withdraw(ctx context.Context, in *WithdrawRequest, opts ...grpc.CallOption) (*TransactionReply, error)
+	Refund(ctx context.Context, in *RefundRequest, opts ...grpc.CallOption) (*TransactionReply, error)
+}
+
+type paymentsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewPaymentsClient(cc *grpc.ClientConn) PaymentsClient {
+	return &paymentsClient{cc}
+}
+
+func (c *paymentsClient) GetBalance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceReply, error) {
+	out := new(BalanceReply)
+	err := c.cc.Invoke(ctx, "/coins.Payments/GetBalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsClient) ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsReply, error) {
+	out := new(ListTransactionsReply)
+	err := c.cc.Invoke(ctx, "/coins.Payments/ListTransactions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsClient) Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransactionReply, error) {
+	out := new(TransactionReply)
+	err := c.cc.Invoke(ctx, "/coins.Payments/Transfer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsClient) TopUp(ctx context.Context, in *TopUpRequest, opts ...grpc.CallOption) (*TopUpReply, error) {
+	out := new(TopUpReply)
+	err := c.cc.Invoke(ctx, "/coins.Payments/TopUp", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsClient) Withdraw(ctx context.Context, in *WithdrawRequest, opts ...grpc.CallOption) (*TransactionReply, error) {
+	out := new(TransactionReply)
+	err := c.cc.Invoke(ctx, "/coins.Payments/Withdraw", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsClient) Refund(ctx context.Context, in *RefundRequest, opts ...grpc.CallOption) (*TransactionReply, error) {
+	out := new(TransactionReply)
+	err := c.cc.Invoke(ctx, "/coins.Payments/Refund", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PaymentsServer is the server API for Payments service.
+type PaymentsServer interface {
+	GetBalance(context.Context, *BalanceRequest) (*BalanceReply, error)
+	ListTransactions(context.Context, *ListTransactionsRequest) (*ListTransactionsReply, error)
+	Transfer(context.Context, *TransferRequest) (*TransactionReply, error)
+	TopUp(context.Context, *TopUpRequest) (*TopUpReply, error)
+	Withdraw(context.Context, *WithdrawRequest) (*TransactionReply, error)
+	Refund(context.Context, *RefundRequest) (*TransactionReply, error)
+}
+
+// UnimplementedPaymentsServer can be embedded to have forward compatible implementations.
+type UnimplementedPaymentsServer struct {
+}
+
+func (*UnimplementedPaymentsServer) GetBalance(ctx context.Context, req *BalanceRequest) (*BalanceReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
+}
+func (*UnimplementedPaymentsServer) ListTransactions(ctx context.Context, req *ListTransactionsRequest) (*ListTransactionsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTransactions not implemented")
+}
+func (*UnimplementedPaymentsServer) Transfer(ctx context.Context, req *TransferRequest) (*TransactionReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Transfer not implemented")
+}
+func (*UnimplementedPaymentsServer) TopUp(ctx context.Context, req *TopUpRequest) (*TopUpReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TopUp not implemented")
+}
+func (*UnimplementedPaymentsServer) Withdraw(ctx context.Context, req *WithdrawRequest) (*TransactionReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Withdraw not implemented")
+}
+func (*UnimplementedPaymentsServer) Refund(ctx context.Context, req *RefundRequest) (*TransactionReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Refund not implemented")
+}
+
+func RegisterPaymentsServer(s *grpc.Server, srv PaymentsServer) {
+	s.RegisterService(&_Payments_serviceDesc, srv)
+}
+
+func _Payments_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsServer).GetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/coins.Payments/GetBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsServer).GetBalance(ctx, req.(*BalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Payments_ListTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsServer).ListTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/coins.Payments/ListTransactions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsServer).ListTransactions(ctx, req.(*ListTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Payments_Transfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsServer).Transfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/coins.Payments/Transfer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsServer).Transfer(ctx, req.(*TransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Payments_TopUp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopUpRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsServer).TopUp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/coins.Payments/TopUp",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsServer).TopUp(ctx, req.(*TopUpRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Payments_Withdraw_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WithdrawRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsServer).Withdraw(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/coins.Payments/Withdraw",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsServer).Withdraw(ctx, req.(*WithdrawRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Payments_Refund_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsServer).Refund(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/coins.Payments/Refund",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsServer).Refund(ctx, req.(*RefundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Payments_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "coins.Payments",
+	HandlerType: (*PaymentsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBalance",
+			Handler:    _Payments_GetBalance_Handler,
+		},
+		{
+			MethodName: "ListTransactions",
+			Handler:    _Payments_ListTransactions_Handler,
+		},
+		{
+			MethodName: "Transfer",
+			Handler:    _Payments_Transfer_Handler,
+		},
+		{
+			MethodName: "TopUp",
+			Handler:    _Payments_TopUp_Handler,
+		},
+		{
+			MethodName: "Withdraw",
+			Handler:    _Payments_Withdraw_Handler,
+		},
+		{
+			MethodName: "Refund",
+			Handler:    _Payments_Refund_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "payment.proto",
+}
@@ -1,7 +1,9 @@
 package account
 
 import (
+	"coins/pkg/metadata"
 	"context"
+	"strconv"
 
 	"github.com/go-kit/kit/endpoint"
 )
@@ -9,22 +11,33 @@ import (
 func makeAddAccountEndpoint(s Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(addAccountRequest)
-		account, err := s.Store(ctx, req.FirstName, req.LastName)
+		account, err := s.Store(ctx, req.CustomerID, req.FirstName, req.LastName, req.ExternalID, req.Tier, req.Metadata)
 		return addAccountResponse{Account: account, Err: err}, nil
 	}
 }
 
 type addAccountRequest struct {
-	FirstName string
-	LastName  string
+	CustomerID int64
+	FirstName  string
+	LastName   string
+	ExternalID string
+	Tier       Tier
+	Metadata   metadata.Metadata
 }
 
 type addAccountResponse struct {
 	Account *Account `json:"account,omitempty"`
-	Err     error    `json:"error,omitempty"`
+	Err     error    `json:"-"`
 }
 
-func (r addAccountResponse) error() error { return r.Err }
+func (r addAccountResponse) Failed() error { return r.Err }
+
+func (r addAccountResponse) Location() string {
+	if r.Account == nil {
+		return ""
+	}
+	return strconv.FormatInt(r.Account.ID, 10)
+}
 
 func makeGetAccountEndpoint(s Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
@@ -40,24 +53,106 @@ type getAccountRequest struct {
 
 type getAccountResponse struct {
 	Account *Account `json:"account,omitempty"`
-	Err     error    `json:"err,omitempty"`
+	Err     error    `json:"-"`
 }
 
-func (r getAccountResponse) error() error { return r.Err }
+func (r getAccountResponse) Failed() error { return r.Err }
 
 func makeListAccountsEndpoint(s Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		_ = request.(listAccountsRequest)
-		accounts, err := s.List(ctx)
-		return listAccountsResponse{Accounts: accounts, Err: err}, err
+		req := request.(listAccountsRequest)
+		page, err := s.List(ctx, req.Filter)
+		if err != nil {
+			return listAccountsResponse{Err: err}, err
+		}
+		return listAccountsResponse{
+			Accounts:   page.Accounts,
+			NextCursor: encodeCursor(page.Next),
+			Total:      page.Total,
+		}, nil
 	}
 }
 
-type listAccountsRequest struct{}
+type listAccountsRequest struct {
+	Filter ListFilter
+}
 
 type listAccountsResponse struct {
-	Accounts []*Account `json:"accounts,omitempty"`
-	Err      error      `json:"err,omitempty"`
+	Accounts   []*Account `json:"accounts,omitempty"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+	Total      *int64     `json:"total,omitempty"`
+	Err        error      `json:"-"`
+}
+
+func (r listAccountsResponse) Failed() error { return r.Err }
+
+func makeUpdateAccountEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateAccountRequest)
+		account, err := s.Update(ctx, req.ID, req.FirstName, req.LastName)
+		return getAccountResponse{Account: account, Err: err}, err
+	}
+}
+
+type updateAccountRequest struct {
+	ID        int64
+	FirstName *string
+	LastName  *string
+}
+
+// makeSetStatusEndpoint build endpoint for status transition without parameters, like Freeze or Reopen
+func makeSetStatusEndpoint(transition func(ctx context.Context, id int64) (*Account, error)) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getAccountRequest)
+		account, err := transition(ctx, req.ID)
+		return getAccountResponse{Account: account, Err: err}, err
+	}
+}
+
+func makeCloseAccountEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(closeAccountRequest)
+		account, err := s.Close(ctx, req.ID, req.SweepTo)
+		return getAccountResponse{Account: account, Err: err}, err
+	}
+}
+
+type closeAccountRequest struct {
+	ID      int64
+	SweepTo int64
+}
+
+func makeGetLimitsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getAccountRequest)
+		limits, err := s.Limits(ctx, req.ID)
+		return getLimitsResponse{Limits: limits, Err: err}, err
+	}
+}
+
+type getLimitsResponse struct {
+	Limits []*Limits `json:"limits"`
+	Err    error     `json:"-"`
+}
+
+func (r getLimitsResponse) Failed() error { return r.Err }
+
+func makeSetLimitsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(setLimitsRequest)
+		limits, err := s.SetLimits(ctx, req.ID, &req.Limits)
+		return setLimitsResponse{Limits: limits, Err: err}, err
+	}
+}
+
+type setLimitsRequest struct {
+	ID     int64
+	Limits Limits
+}
+
+type setLimitsResponse struct {
+	Limits *Limits `json:"limits,omitempty"`
+	Err    error   `json:"-"`
 }
 
-func (r listAccountsResponse) error() error { return r.Err }
+func (r setLimitsResponse) Failed() error { return r.Err }
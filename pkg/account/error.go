@@ -1,6 +1,9 @@
 package account
 
-import "fmt"
+import (
+	"coins/pkg/problem"
+	"fmt"
+)
 
 // ErrNotFound - raised when account not found
 type ErrNotFound struct {
@@ -10,3 +13,93 @@ type ErrNotFound struct {
 func (e ErrNotFound) Error() string {
 	return fmt.Sprintf("account with ID %d not found", e.ID)
 }
+
+// Code implements problem.Coder
+func (e ErrNotFound) Code() string {
+	return problem.CodeAccountNotFound
+}
+
+// ErrFrozen raised when frozen account is debited or credited
+type ErrFrozen struct {
+	ID int64
+}
+
+func (e ErrFrozen) Error() string {
+	return fmt.Sprintf("account with ID %d is frozen", e.ID)
+}
+
+// Code implements problem.Coder
+func (e ErrFrozen) Code() string {
+	return problem.CodeAccountFrozen
+}
+
+// ErrClosed raised when closed account is debited, credited or updated
+type ErrClosed struct {
+	ID int64
+}
+
+func (e ErrClosed) Error() string {
+	return fmt.Sprintf("account with ID %d is closed", e.ID)
+}
+
+// Code implements problem.Coder
+func (e ErrClosed) Code() string {
+	return problem.CodeAccountClosed
+}
+
+// ErrInvalidTransition raised when account can't be moved from its current status to the requested one
+type ErrInvalidTransition struct {
+	ID       int64
+	From, To Status
+}
+
+func (e ErrInvalidTransition) Error() string {
+	return fmt.Sprintf("account with ID %d can't become %s, it is %s", e.ID, e.To, e.From)
+}
+
+// Code implements problem.Coder
+func (e ErrInvalidTransition) Code() string {
+	return problem.CodeInvalidStatusTransition
+}
+
+// ErrNonZeroBalance raised when account with funds, active holds or pending withdrawals is closed without sweep target
+type ErrNonZeroBalance struct {
+	ID int64
+}
+
+func (e ErrNonZeroBalance) Error() string {
+	return fmt.Sprintf("account with ID %d has non-zero balance", e.ID)
+}
+
+// Code implements problem.Coder
+func (e ErrNonZeroBalance) Code() string {
+	return problem.CodeNonZeroBalance
+}
+
+// ErrCustomerNotFound raised when account is created for unknown customer
+type ErrCustomerNotFound struct {
+	ID int64
+}
+
+func (e ErrCustomerNotFound) Error() string {
+	return fmt.Sprintf("customer with ID %d not found", e.ID)
+}
+
+// Code implements problem.Coder
+func (e ErrCustomerNotFound) Code() string {
+	return problem.CodeCustomerNotFound
+}
+
+// ErrDuplicateExternalID raised when account with the same external ID already exists
+type ErrDuplicateExternalID struct {
+	ExternalID string
+}
+
+func (e ErrDuplicateExternalID) Error() string {
+	return fmt.Sprintf("account with external ID %q already exists", e.ExternalID)
+}
+
+// Code implements problem.Coder
+func (e ErrDuplicateExternalID) Code() string {
+	return problem.CodeDuplicateExternalID
+}
@@ -0,0 +1,58 @@
+package account
+
+import "coins/pkg/metadata"
+
+// page size limits of List
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
+
+// SortField field accounts are sorted by, ties are broken by ID
+type SortField string
+
+// sort fields
+const (
+	SortByID        SortField = "id"
+	SortByFirstName SortField = "first_name"
+	SortByLastName  SortField = "last_name"
+)
+
+// Order of listed accounts
+type Order string
+
+// account orders
+const (
+	OrderAsc  Order = "asc"
+	OrderDesc Order = "desc"
+)
+
+// Cursor position in the list of accounts, points to the last account of the previous page.
+// Value holds the sort field of that account and is empty when sorted by ID
+type Cursor struct {
+	Value string
+	ID    int64
+}
+
+// ListFilter search and page of List, zero values don't filter
+type ListFilter struct {
+	After *Cursor
+	Limit int
+	Sort  SortField
+	Order Order
+	// Query case-insensitive prefix of first or last name
+	Query string
+	// WithTotal request total number of accounts matching Query
+	WithTotal bool
+	// CustomerID list only accounts of the customer
+	CustomerID int64
+	ExternalID string
+	Metadata   metadata.Filter
+}
+
+// Page page of accounts, Next is nil on the last page and Total is nil unless requested
+type Page struct {
+	Accounts []*Account
+	Next     *Cursor
+	Total    *int64
+}
@@ -0,0 +1,16 @@
+package account
+
+import "coins/pkg/money"
+
+// Limits spending settings of account wallet in Currency, nil settings aren't enforced. Balance of the wallet may
+// go below zero by Overdraft or must stay at least MinBalance. MaxTransaction caps single debit or hold, DailyOutflow and
+// MonthlyOutflow cap debits by transfers, captures and withdrawals within last 24 hours and 30 days with active holds,
+// the same rolling windows as velocity limits of account tier. Refunds return received funds and only keep the balance floor
+type Limits struct {
+	Currency       money.Currency `json:"currency"`
+	Overdraft      *money.Amount  `json:"overdraft,omitempty"`
+	MinBalance     *money.Amount  `json:"min_balance,omitempty"`
+	MaxTransaction *money.Amount  `json:"max_transaction,omitempty"`
+	DailyOutflow   *money.Amount  `json:"daily_outflow_cap,omitempty"`
+	MonthlyOutflow *money.Amount  `json:"monthly_outflow_cap,omitempty"`
+}
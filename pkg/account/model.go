@@ -1,16 +1,76 @@
 package account
 
-// Account model
+import "coins/pkg/metadata"
+
+// Status of account
+type Status string
+
+// account statuses, frozen and closed accounts can't be debited or credited
+const (
+	StatusActive Status = "active"
+	StatusFrozen Status = "frozen"
+	StatusClosed Status = "closed"
+)
+
+// transitions allowed transitions between statuses
+var transitions = map[Status][]Status{
+	StatusActive: {StatusFrozen, StatusClosed},
+	StatusFrozen: {StatusActive, StatusClosed},
+	StatusClosed: {StatusActive},
+}
+
+// canBecome return true when account with status s can be moved to status to
+func (s Status) canBecome(to Status) bool {
+	for _, t := range transitions[s] {
+		if t == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Tier of account, payment velocity limits are configured per tier
+type Tier string
+
+// account tiers
+const (
+	TierStandard Tier = "standard"
+	TierPremium  Tier = "premium"
+)
+
+// tiers known tiers
+var tiers = map[Tier]bool{TierStandard: true, TierPremium: true}
+
+// Valid return true for known tier
+func (t Tier) Valid() bool {
+	return tiers[t]
+}
+
+// Account model, CustomerID is zero for accounts created before customers were introduced.
+// ExternalID is optional unique client reference
 type Account struct {
-	ID        int64  `json:"id"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
+	ID         int64             `json:"id"`
+	CustomerID int64             `json:"customer_id,omitempty"`
+	FirstName  string            `json:"first_name"`
+	LastName   string            `json:"last_name"`
+	Status     Status            `json:"status"`
+	Tier       Tier              `json:"tier"`
+	ExternalID string            `json:"external_id,omitempty"`
+	Metadata   metadata.Metadata `json:"metadata,omitempty"`
 }
 
 // New create new account model
-func New(firstName, lastName string) *Account {
+func New(customerID int64, firstName, lastName string) *Account {
 	return &Account{
-		FirstName: firstName,
-		LastName:  lastName,
+		CustomerID: customerID,
+		FirstName:  firstName,
+		LastName:   lastName,
+		Status:     StatusActive,
+		Tier:       TierStandard,
 	}
 }
+
+// SameCustomer return true when both accounts belong to the same customer
+func (a *Account) SameCustomer(b *Account) bool {
+	return a.CustomerID != 0 && a.CustomerID == b.CustomerID
+}
@@ -1,28 +1,67 @@
 package account
 
-import "context"
+import (
+	"coins/pkg/metadata"
+	"context"
+
+	"github.com/pkg/errors"
+)
 
 // Repository interface
 type Repository interface {
-	List(context.Context) ([]*Account, error)
+	List(context.Context, ListFilter) (*Page, error)
 	Get(ctx context.Context, id int64) (*Account, error)
 	Store(context.Context, *Account) (*Account, error)
+	Update(context.Context, *Account) (*Account, error)
+	// SetStatus move account from status `from` to `to`, raise ErrInvalidTransition when account isn't in status `from`
+	SetStatus(ctx context.Context, id int64, from, to Status) error
+	// GetLimits return spending limits of every wallet of account which has them
+	GetLimits(ctx context.Context, id int64) ([]*Limits, error)
+	// StoreLimits create or replace spending limits of account wallet
+	StoreLimits(ctx context.Context, id int64, l *Limits) error
+}
+
+// Sweeper moves funds out of account being closed
+type Sweeper interface {
+	// Sweep transfer all funds of closed account `from` to account `to`. When `to` is zero it only checks
+	// that account has no funds. Raise ErrNonZeroBalance when funds can't be swept
+	Sweep(ctx context.Context, from, to int64) error
 }
 
 // Service interface
 type Service interface {
-	List(context.Context) ([]*Account, error)
+	List(context.Context, ListFilter) (*Page, error)
 	Get(ctx context.Context, id int64) (*Account, error)
-	Store(ctx context.Context, firstName, lastName string) (*Account, error)
+	Store(ctx context.Context, customerID int64, firstName, lastName, externalID string, tier Tier, md metadata.Metadata) (*Account, error)
+	Update(ctx context.Context, id int64, firstName, lastName *string) (*Account, error)
+	Freeze(ctx context.Context, id int64) (*Account, error)
+	Unfreeze(ctx context.Context, id int64) (*Account, error)
+	Close(ctx context.Context, id, sweepTo int64) (*Account, error)
+	Reopen(ctx context.Context, id int64) (*Account, error)
+	Limits(ctx context.Context, id int64) ([]*Limits, error)
+	SetLimits(ctx context.Context, id int64, l *Limits) (*Limits, error)
 }
 
 type service struct {
-	repo Repository
+	repo    Repository
+	sweeper Sweeper
 }
 
-// List return list of accounts without paging
-func (s *service) List(ctx context.Context) ([]*Account, error) {
-	return s.repo.List(ctx)
+// List return page of accounts matching filter, sorted by ID in ascending order by default
+func (s *service) List(ctx context.Context, filter ListFilter) (*Page, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageSize
+	}
+	if filter.Limit > MaxPageSize {
+		filter.Limit = MaxPageSize
+	}
+	if filter.Sort == "" {
+		filter.Sort = SortByID
+	}
+	if filter.Order == "" {
+		filter.Order = OrderAsc
+	}
+	return s.repo.List(ctx, filter)
 }
 
 // Get return Account with requested id or return ErrNotFound error
@@ -30,12 +69,122 @@ func (s *service) Get(ctx context.Context, id int64) (*Account, error) {
 	return s.repo.Get(ctx, id)
 }
 
-// Store new account with providerd first and lastname
-func (s *service) Store(ctx context.Context, firstName, lastName string) (*Account, error) {
-	return s.repo.Store(ctx, New(firstName, lastName))
+// Store new account with providerd first and lastname, owned by customer when customerID isn't zero.
+// Account is TierStandard when tier isn't set.
+// Raise ErrCustomerNotFound for unknown customer and ErrDuplicateExternalID when externalID is already used
+func (s *service) Store(ctx context.Context, customerID int64, firstName, lastName, externalID string, tier Tier, md metadata.Metadata) (*Account, error) {
+	a := New(customerID, firstName, lastName)
+	a.ExternalID = externalID
+	if tier != "" {
+		a.Tier = tier
+	}
+	a.Metadata = md
+	return s.repo.Store(ctx, a)
+}
+
+// Limits return spending limits of account wallets, raise ErrNotFound for unknown account
+func (s *service) Limits(ctx context.Context, id int64) ([]*Limits, error) {
+	if _, err := s.repo.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetLimits(ctx, id)
+}
+
+// SetLimits replace spending limits of account wallet, settings which aren't provided are removed.
+// Raise ErrClosed for closed account
+func (s *service) SetLimits(ctx context.Context, id int64, l *Limits) (*Limits, error) {
+	a, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if a.Status == StatusClosed {
+		return nil, ErrClosed{ID: id}
+	}
+	if err := s.repo.StoreLimits(ctx, id, l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Update change provided(non-nil) name fields, raise ErrClosed for closed account
+func (s *service) Update(ctx context.Context, id int64, firstName, lastName *string) (*Account, error) {
+	a, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if a.Status == StatusClosed {
+		return nil, ErrClosed{ID: id}
+	}
+	if firstName != nil {
+		a.FirstName = *firstName
+	}
+	if lastName != nil {
+		a.LastName = *lastName
+	}
+	return s.repo.Update(ctx, a)
+}
+
+// Freeze active account, frozen account can't be debited or credited
+func (s *service) Freeze(ctx context.Context, id int64) (*Account, error) {
+	return s.setStatus(ctx, id, StatusActive, StatusFrozen)
+}
+
+// Unfreeze frozen account
+func (s *service) Unfreeze(ctx context.Context, id int64) (*Account, error) {
+	return s.setStatus(ctx, id, StatusFrozen, StatusActive)
+}
+
+// Reopen closed account
+func (s *service) Reopen(ctx context.Context, id int64) (*Account, error) {
+	return s.setStatus(ctx, id, StatusClosed, StatusActive)
+}
+
+// Close active or frozen account. Account is closed first, so no new payments can reach it, then its funds are swept
+// to `sweepTo` account. When sweepTo is zero account must have no funds, otherwise ErrNonZeroBalance is raised.
+// Account gets its previous status back when sweep fails
+func (s *service) Close(ctx context.Context, id, sweepTo int64) (*Account, error) {
+	a, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !a.Status.canBecome(StatusClosed) {
+		return nil, ErrInvalidTransition{ID: id, From: a.Status, To: StatusClosed}
+	}
+	if sweepTo != 0 {
+		if _, err := s.repo.Get(ctx, sweepTo); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.SetStatus(ctx, id, a.Status, StatusClosed); err != nil {
+		return nil, err
+	}
+	if err := s.sweeper.Sweep(ctx, id, sweepTo); err != nil {
+		if rerr := s.repo.SetStatus(ctx, id, StatusClosed, a.Status); rerr != nil {
+			return nil, errors.Wrapf(rerr, "unable to restore status of account with ID %d after failed sweep: %v", id, err)
+		}
+		return nil, err
+	}
+	a.Status = StatusClosed
+	return a, nil
+}
+
+// setStatus move account in status `from` to status `to`
+func (s *service) setStatus(ctx context.Context, id int64, from, to Status) (*Account, error) {
+	a, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if a.Status != from || !from.canBecome(to) {
+		return nil, ErrInvalidTransition{ID: id, From: a.Status, To: to}
+	}
+	if err := s.repo.SetStatus(ctx, id, from, to); err != nil {
+		return nil, err
+	}
+	a.Status = to
+	return a, nil
 }
 
-// NewService build new Service
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+// NewService build new Service, sweeper moves funds out of closed accounts
+func NewService(repo Repository, sweeper Sweeper) Service {
+	return &service{repo: repo, sweeper: sweeper}
 }
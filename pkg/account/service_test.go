@@ -0,0 +1,127 @@
+package account
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// stubRepo keep accounts in memory and record filter passed to the repository, methods not needed by tests
+// aren't implemented
+type stubRepo struct {
+	Repository
+	accounts map[int64]*Account
+	filter   ListFilter
+}
+
+func newStubRepo(accounts ...*Account) *stubRepo {
+	r := &stubRepo{accounts: make(map[int64]*Account)}
+	for _, a := range accounts {
+		r.accounts[a.ID] = a
+	}
+	return r
+}
+
+func (r *stubRepo) List(_ context.Context, filter ListFilter) (*Page, error) {
+	r.filter = filter
+	return &Page{}, nil
+}
+
+func (r *stubRepo) Get(_ context.Context, id int64) (*Account, error) {
+	a, ok := r.accounts[id]
+	if !ok {
+		return nil, ErrNotFound{ID: id}
+	}
+	c := *a
+	return &c, nil
+}
+
+func (r *stubRepo) SetStatus(_ context.Context, id int64, from, to Status) error {
+	a, ok := r.accounts[id]
+	if !ok {
+		return ErrNotFound{ID: id}
+	}
+	if a.Status != from {
+		return ErrInvalidTransition{ID: id, From: a.Status, To: to}
+	}
+	a.Status = to
+	return nil
+}
+
+// stubSweeper fail sweeps with err
+type stubSweeper struct {
+	err error
+}
+
+func (s stubSweeper) Sweep(context.Context, int64, int64) error {
+	return s.err
+}
+
+func testAccount(id int64, status Status) *Account {
+	a := New(0, "John", "Doe")
+	a.ID, a.Status = id, status
+	return a
+}
+
+func TestListPageSize(t *testing.T) {
+	tests := []struct {
+		filter ListFilter
+		want   ListFilter
+	}{
+		{ListFilter{}, ListFilter{Limit: DefaultPageSize, Sort: SortByID, Order: OrderAsc}},
+		{ListFilter{Limit: 10, Sort: SortByLastName, Order: OrderDesc}, ListFilter{Limit: 10, Sort: SortByLastName, Order: OrderDesc}},
+		{ListFilter{Limit: MaxPageSize + 1, Query: "jo"}, ListFilter{Limit: MaxPageSize, Sort: SortByID, Order: OrderAsc, Query: "jo"}},
+	}
+	for _, tt := range tests {
+		repo := newStubRepo()
+		if _, err := NewService(repo, nil).List(context.Background(), tt.filter); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(repo.filter, tt.want) {
+			t.Errorf("%+v: got %+v, want %+v", tt.filter, repo.filter, tt.want)
+		}
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	type op func(Service) (*Account, error)
+	freeze := func(s Service) (*Account, error) { return s.Freeze(context.Background(), 1) }
+	unfreeze := func(s Service) (*Account, error) { return s.Unfreeze(context.Background(), 1) }
+	reopen := func(s Service) (*Account, error) { return s.Reopen(context.Background(), 1) }
+	closeTo := func(sweepTo int64) op {
+		return func(s Service) (*Account, error) { return s.Close(context.Background(), 1, sweepTo) }
+	}
+	tests := []struct {
+		name    string
+		status  Status
+		op      op
+		sweeper stubSweeper
+		want    Status
+		err     error
+	}{
+		{"freeze active", StatusActive, freeze, stubSweeper{}, StatusFrozen, nil},
+		{"freeze frozen", StatusFrozen, freeze, stubSweeper{}, StatusFrozen, ErrInvalidTransition{ID: 1, From: StatusFrozen, To: StatusFrozen}},
+		{"unfreeze frozen", StatusFrozen, unfreeze, stubSweeper{}, StatusActive, nil},
+		{"unfreeze closed", StatusClosed, unfreeze, stubSweeper{}, StatusClosed, ErrInvalidTransition{ID: 1, From: StatusClosed, To: StatusActive}},
+		{"reopen closed", StatusClosed, reopen, stubSweeper{}, StatusActive, nil},
+		{"reopen active", StatusActive, reopen, stubSweeper{}, StatusActive, ErrInvalidTransition{ID: 1, From: StatusActive, To: StatusActive}},
+		{"close active", StatusActive, closeTo(2), stubSweeper{}, StatusClosed, nil},
+		{"close frozen", StatusFrozen, closeTo(0), stubSweeper{}, StatusClosed, nil},
+		{"close closed", StatusClosed, closeTo(0), stubSweeper{}, StatusClosed, ErrInvalidTransition{ID: 1, From: StatusClosed, To: StatusClosed}},
+		{"close to unknown account", StatusActive, closeTo(3), stubSweeper{}, StatusActive, ErrNotFound{ID: 3}},
+		{"close with funds", StatusFrozen, closeTo(0), stubSweeper{err: ErrNonZeroBalance{ID: 1}}, StatusFrozen, ErrNonZeroBalance{ID: 1}},
+	}
+	for _, tt := range tests {
+		repo := newStubRepo(testAccount(1, tt.status), testAccount(2, StatusActive))
+		a, err := tt.op(NewService(repo, tt.sweeper))
+		if err != tt.err {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, tt.err)
+		}
+		if err == nil && a.Status != tt.want {
+			t.Errorf("%s: got account status %s, want %s", tt.name, a.Status, tt.want)
+		}
+		if got := repo.accounts[1].Status; got != tt.want {
+			t.Errorf("%s: got stored status %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -1,12 +1,24 @@
 package account
 
 import (
+	"coins/pkg/auth"
+	"coins/pkg/metadata"
+	"coins/pkg/money"
+	"coins/pkg/problem"
+	"coins/pkg/ratelimit"
+	"coins/pkg/response"
+	"coins/pkg/validation"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/go-kit/kit/log"
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/gorilla/mux"
 )
@@ -19,62 +31,116 @@ func (e errBadRequest) Error() string {
 	return e.Msg
 }
 
-// MakeHandler build handlers for account transport
-func MakeHandler(as Service) http.Handler {
+// Code implements problem.Coder
+func (e errBadRequest) Code() string {
+	return problem.CodeInvalidParameter
+}
+
+// MakeHandler build handlers for account transport of API v2, breaking changes land here while v1 stays frozen
+func MakeHandler(as Service, authn auth.Authenticator, rl *ratelimit.Limiter, logger log.Logger) http.Handler {
+	const prefix = "/v2/account/"
+	enc := response.NewEncoder(logger, false, 0)
 	opts := []kithttp.ServerOption{
-		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerBefore(kithttp.PopulateRequestContext, problem.PopulateRequestID, auth.HTTPToContext),
+		kithttp.ServerAfter(problem.SetRequestIDHeader),
+		kithttp.ServerErrorEncoder(enc.EncodeError),
 	}
+	read, write := rl.Wrap(auth.Require(authn, auth.ScopeAccountsRead)), rl.Wrap(auth.Require(authn, auth.ScopeAccountsWrite))
 
 	addAccountHandler := kithttp.NewServer(
-		makeAddAccountEndpoint(as),
+		write(validation.Middleware(makeAddAccountEndpoint(as))),
 		decodeAddAccountRequest,
-		encodeResponse,
+		enc.CreatedIn(prefix),
 		opts...,
 	)
 
 	getAccountHandler := kithttp.NewServer(
-		makeGetAccountEndpoint(as),
+		read(validation.Middleware(makeGetAccountEndpoint(as))),
 		decodeGetAccountRequest,
-		encodeResponse,
+		enc.OK(),
 		opts...,
 	)
 
 	listAccountsHandler := kithttp.NewServer(
-		makeListAccountsEndpoint(as),
+		read(validation.Middleware(makeListAccountsEndpoint(as))),
 		decodeListAccountsRequest,
-		encodeResponse,
+		enc.OK(),
+		opts...,
+	)
+
+	updateAccountHandler := kithttp.NewServer(
+		write(validation.Middleware(makeUpdateAccountEndpoint(as))),
+		decodeUpdateAccountRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	freezeAccountHandler := kithttp.NewServer(
+		write(validation.Middleware(makeSetStatusEndpoint(as.Freeze))),
+		decodeGetAccountRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	unfreezeAccountHandler := kithttp.NewServer(
+		write(validation.Middleware(makeSetStatusEndpoint(as.Unfreeze))),
+		decodeGetAccountRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	closeAccountHandler := kithttp.NewServer(
+		write(validation.Middleware(makeCloseAccountEndpoint(as))),
+		decodeCloseAccountRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	reopenAccountHandler := kithttp.NewServer(
+		write(validation.Middleware(makeSetStatusEndpoint(as.Reopen))),
+		decodeGetAccountRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	getLimitsHandler := kithttp.NewServer(
+		read(validation.Middleware(makeGetLimitsEndpoint(as))),
+		decodeGetAccountRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	setLimitsHandler := kithttp.NewServer(
+		write(validation.Middleware(makeSetLimitsEndpoint(as))),
+		decodeSetLimitsRequest,
+		enc.OK(),
 		opts...,
 	)
 
 	r := mux.NewRouter()
 
-	r.Handle("/account/v1/", addAccountHandler).Methods("POST")
-	r.Handle("/account/v1/{id}", getAccountHandler).Methods("GET")
-	r.Handle("/account/v1/", listAccountsHandler).Methods("GET")
+	r.Handle(prefix, addAccountHandler).Methods("POST")
+	r.Handle(prefix+"{id}", getAccountHandler).Methods("GET")
+	r.Handle(prefix, listAccountsHandler).Methods("GET")
+	r.Handle(prefix+"{id}", updateAccountHandler).Methods("PATCH")
+	r.Handle(prefix+"{id}/freeze", freezeAccountHandler).Methods("POST")
+	r.Handle(prefix+"{id}/unfreeze", unfreezeAccountHandler).Methods("POST")
+	r.Handle(prefix+"{id}/close", closeAccountHandler).Methods("POST")
+	r.Handle(prefix+"{id}/reopen", reopenAccountHandler).Methods("POST")
+	r.Handle(prefix+"{id}/limits", getLimitsHandler).Methods("GET")
+	r.Handle(prefix+"{id}/limits/{currency}", setLimitsHandler).Methods("PUT")
 
 	return r
 }
 
-// encode errors from business-logic
-func encodeError(_ context.Context, err error, w http.ResponseWriter) {
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	switch err.(type) {
-	case ErrNotFound:
-		w.WriteHeader(http.StatusNotFound)
-	case errBadRequest:
-		w.WriteHeader(http.StatusBadRequest)
-	default:
-		w.WriteHeader(http.StatusInternalServerError)
-	}
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"error": err.Error(),
-	})
-}
-
 func decodeAddAccountRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	var body struct {
-		FirstName string `json:"first_name"`
-		LastName  string `json:"last_name"`
+		CustomerID int64             `json:"customer_id"`
+		FirstName  string            `json:"first_name"`
+		LastName   string            `json:"last_name"`
+		ExternalID string            `json:"external_id"`
+		Tier       Tier              `json:"tier"`
+		Metadata   metadata.Metadata `json:"metadata"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -82,8 +148,12 @@ func decodeAddAccountRequest(_ context.Context, r *http.Request) (interface{}, e
 	}
 
 	return addAccountRequest{
-		FirstName: body.FirstName,
-		LastName:  body.LastName,
+		CustomerID: body.CustomerID,
+		FirstName:  body.FirstName,
+		LastName:   body.LastName,
+		ExternalID: body.ExternalID,
+		Tier:       body.Tier,
+		Metadata:   body.Metadata,
 	}, nil
 }
 
@@ -100,20 +170,138 @@ func decodeGetAccountRequest(_ context.Context, r *http.Request) (interface{}, e
 	return getAccountRequest{ID: id}, nil
 }
 
+func decodeUpdateAccountRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	req, err := decodeGetAccountRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		FirstName *string `json:"first_name"`
+		LastName  *string `json:"last_name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return updateAccountRequest{
+		ID:        req.(getAccountRequest).ID,
+		FirstName: body.FirstName,
+		LastName:  body.LastName,
+	}, nil
+}
+
+// decodeCloseAccountRequest decode optional body with sweep target
+func decodeCloseAccountRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	req, err := decodeGetAccountRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	id := req.(getAccountRequest).ID
+	var body struct {
+		SweepTo int64 `json:"sweep_to"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return closeAccountRequest{ID: id, SweepTo: body.SweepTo}, nil
+}
+
+// decodeSetLimitsRequest decode spending limits of wallet in currency from path
+func decodeSetLimitsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	req, err := decodeGetAccountRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	currency, err := money.ParseCurrency(mux.Vars(r)["currency"])
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Overdraft      *money.Amount `json:"overdraft"`
+		MinBalance     *money.Amount `json:"min_balance"`
+		MaxTransaction *money.Amount `json:"max_transaction"`
+		DailyOutflow   *money.Amount `json:"daily_outflow_cap"`
+		MonthlyOutflow *money.Amount `json:"monthly_outflow_cap"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return setLimitsRequest{
+		ID: req.(getAccountRequest).ID,
+		Limits: Limits{
+			Currency:       currency,
+			Overdraft:      body.Overdraft,
+			MinBalance:     body.MinBalance,
+			MaxTransaction: body.MaxTransaction,
+			DailyOutflow:   body.DailyOutflow,
+			MonthlyOutflow: body.MonthlyOutflow,
+		},
+	}, nil
+}
+
 func decodeListAccountsRequest(_ context.Context, r *http.Request) (interface{}, error) {
-	return listAccountsRequest{}, nil
+	var (
+		f   ListFilter
+		err error
+	)
+	q := r.URL.Query()
+	if v := q.Get("limit"); v != "" {
+		if f.Limit, err = strconv.Atoi(v); err != nil || f.Limit <= 0 {
+			return nil, errBadRequest{Msg: fmt.Sprintf("limit param must be positive int")}
+		}
+	}
+	switch sf := SortField(q.Get("sort")); sf {
+	case "", SortByID, SortByFirstName, SortByLastName:
+		f.Sort = sf
+	default:
+		return nil, errBadRequest{Msg: fmt.Sprintf("sort param must be %s, %s or %s", SortByID, SortByFirstName, SortByLastName)}
+	}
+	switch o := Order(q.Get("order")); o {
+	case "", OrderAsc, OrderDesc:
+		f.Order = o
+	default:
+		return nil, errBadRequest{Msg: fmt.Sprintf("order param must be %s or %s", OrderAsc, OrderDesc)}
+	}
+	if v := q.Get("cursor"); v != "" {
+		if f.After, err = decodeCursor(v); err != nil {
+			return nil, errBadRequest{Msg: fmt.Sprintf("cursor param is invalid")}
+		}
+	}
+	f.Query = strings.TrimSpace(q.Get("q"))
+	if v := q.Get("customer_id"); v != "" {
+		if f.CustomerID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, errBadRequest{Msg: fmt.Sprintf("customer_id param must be int")}
+		}
+	}
+	f.ExternalID = q.Get("external_id")
+	f.Metadata = metadata.FilterFromQuery(q)
+	if v := q.Get("total"); v != "" {
+		if f.WithTotal, err = strconv.ParseBool(v); err != nil {
+			return nil, errBadRequest{Msg: fmt.Sprintf("total param must be bool")}
+		}
+	}
+	return listAccountsRequest{Filter: f}, nil
 }
 
-func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
-	if e, ok := response.(errorer); ok && e.error() != nil {
-		encodeError(ctx, e.error(), w)
-		return nil
+// encodeCursor return opaque cursor string, empty for nil cursor
+func encodeCursor(c *Cursor) string {
+	if c == nil {
+		return ""
 	}
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(http.StatusCreated)
-	return json.NewEncoder(w).Encode(response)
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s", c.ID, c.Value)))
 }
 
-type errorer interface {
-	error() error
+func decodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(string(b), ":", 2)
+	if len(parts) != 2 {
+		return nil, errors.New("malformed cursor")
+	}
+	id, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &Cursor{ID: id, Value: parts[1]}, nil
 }
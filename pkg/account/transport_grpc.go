@@ -0,0 +1,162 @@
+package account
+
+import (
+	"coins/pb"
+	"coins/pkg/auth"
+	"coins/pkg/metadata"
+	"coins/pkg/problem"
+	"coins/pkg/ratelimit"
+	"coins/pkg/response"
+	"coins/pkg/validation"
+	"context"
+
+	"github.com/go-kit/kit/log"
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+)
+
+type grpcServer struct {
+	addAccount      kitgrpc.Handler
+	getAccount      kitgrpc.Handler
+	freezeAccount   kitgrpc.Handler
+	unfreezeAccount kitgrpc.Handler
+	closeAccount    kitgrpc.Handler
+	reopenAccount   kitgrpc.Handler
+	errors          *problem.Encoder
+}
+
+// MakeGRPCServer build gRPC server of account service on the same endpoints as HTTP transport
+func MakeGRPCServer(as Service, authn auth.Authenticator, rl *ratelimit.Limiter, logger log.Logger) pb.AccountsServer {
+	opts := []kitgrpc.ServerOption{
+		kitgrpc.ServerBefore(problem.PopulateGRPCRequestID, auth.GRPCToContext),
+		kitgrpc.ServerAfter(problem.SetGRPCRequestID),
+	}
+	read, write := rl.Wrap(auth.Require(authn, auth.ScopeAccountsRead)), rl.Wrap(auth.Require(authn, auth.ScopeAccountsWrite))
+	return &grpcServer{
+		addAccount: kitgrpc.NewServer(
+			write(validation.Middleware(makeAddAccountEndpoint(as))),
+			decodeGRPCAddAccountRequest,
+			encodeGRPCAccountResponse,
+			opts...,
+		),
+		getAccount: kitgrpc.NewServer(
+			read(validation.Middleware(makeGetAccountEndpoint(as))),
+			decodeGRPCAccountRequest,
+			encodeGRPCAccountResponse,
+			opts...,
+		),
+		freezeAccount: kitgrpc.NewServer(
+			write(validation.Middleware(makeSetStatusEndpoint(as.Freeze))),
+			decodeGRPCAccountRequest,
+			encodeGRPCAccountResponse,
+			opts...,
+		),
+		unfreezeAccount: kitgrpc.NewServer(
+			write(validation.Middleware(makeSetStatusEndpoint(as.Unfreeze))),
+			decodeGRPCAccountRequest,
+			encodeGRPCAccountResponse,
+			opts...,
+		),
+		closeAccount: kitgrpc.NewServer(
+			write(validation.Middleware(makeCloseAccountEndpoint(as))),
+			decodeGRPCCloseAccountRequest,
+			encodeGRPCAccountResponse,
+			opts...,
+		),
+		reopenAccount: kitgrpc.NewServer(
+			write(validation.Middleware(makeSetStatusEndpoint(as.Reopen))),
+			decodeGRPCAccountRequest,
+			encodeGRPCAccountResponse,
+			opts...,
+		),
+		errors: problem.NewEncoder(logger),
+	}
+}
+
+func (s *grpcServer) AddAccount(ctx context.Context, req *pb.AddAccountRequest) (*pb.AccountReply, error) {
+	return s.serve(ctx, s.addAccount, req)
+}
+
+func (s *grpcServer) GetAccount(ctx context.Context, req *pb.AccountRequest) (*pb.AccountReply, error) {
+	return s.serve(ctx, s.getAccount, req)
+}
+
+func (s *grpcServer) FreezeAccount(ctx context.Context, req *pb.AccountRequest) (*pb.AccountReply, error) {
+	return s.serve(ctx, s.freezeAccount, req)
+}
+
+func (s *grpcServer) UnfreezeAccount(ctx context.Context, req *pb.AccountRequest) (*pb.AccountReply, error) {
+	return s.serve(ctx, s.unfreezeAccount, req)
+}
+
+func (s *grpcServer) CloseAccount(ctx context.Context, req *pb.CloseAccountRequest) (*pb.AccountReply, error) {
+	return s.serve(ctx, s.closeAccount, req)
+}
+
+func (s *grpcServer) ReopenAccount(ctx context.Context, req *pb.AccountRequest) (*pb.AccountReply, error) {
+	return s.serve(ctx, s.reopenAccount, req)
+}
+
+func (s *grpcServer) serve(ctx context.Context, h kitgrpc.Handler, req interface{}) (*pb.AccountReply, error) {
+	ctx, rep, err := h.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, s.errors.GRPCError(ctx, err)
+	}
+	return rep.(*pb.AccountReply), nil
+}
+
+func decodeGRPCAddAccountRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.AddAccountRequest)
+	md, err := metadata.Parse(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	return addAccountRequest{
+		CustomerID: req.CustomerId,
+		FirstName:  req.FirstName,
+		LastName:   req.LastName,
+		ExternalID: req.ExternalId,
+		Tier:       Tier(req.Tier),
+		Metadata:   md,
+	}, nil
+}
+
+func decodeGRPCAccountRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.AccountRequest)
+	return getAccountRequest{ID: req.Id}, nil
+}
+
+func decodeGRPCCloseAccountRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.CloseAccountRequest)
+	return closeAccountRequest{ID: req.Id, SweepTo: req.SweepTo}, nil
+}
+
+// encodeGRPCAccountResponse encode any response carrying single account
+func encodeGRPCAccountResponse(_ context.Context, r interface{}) (interface{}, error) {
+	if f, ok := r.(response.Failer); ok && f.Failed() != nil {
+		return nil, f.Failed()
+	}
+	var a *Account
+	switch resp := r.(type) {
+	case addAccountResponse:
+		a = resp.Account
+	case getAccountResponse:
+		a = resp.Account
+	}
+	return &pb.AccountReply{Account: accountToPB(a)}, nil
+}
+
+func accountToPB(a *Account) *pb.Account {
+	if a == nil {
+		return nil
+	}
+	return &pb.Account{
+		Id:         a.ID,
+		CustomerId: a.CustomerID,
+		FirstName:  a.FirstName,
+		LastName:   a.LastName,
+		Status:     string(a.Status),
+		Tier:       string(a.Tier),
+		ExternalId: a.ExternalID,
+		Metadata:   a.Metadata.String(),
+	}
+}
@@ -0,0 +1,48 @@
+package account
+
+import (
+	"coins/pb"
+	"coins/pkg/metadata"
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeGRPCAddAccountRequest(t *testing.T) {
+	tests := []struct {
+		req  *pb.AddAccountRequest
+		want interface{}
+		err  bool
+	}{
+		{&pb.AddAccountRequest{FirstName: "John", LastName: "Doe"}, addAccountRequest{FirstName: "John", LastName: "Doe"}, false},
+		{
+			&pb.AddAccountRequest{CustomerId: 1, FirstName: "John", LastName: "Doe", Tier: "premium", ExternalId: "crm-1", Metadata: `{"source":"web"}`},
+			addAccountRequest{CustomerID: 1, FirstName: "John", LastName: "Doe", Tier: TierPremium, ExternalID: "crm-1", Metadata: metadata.Metadata{"source": "web"}},
+			false,
+		},
+		{&pb.AddAccountRequest{FirstName: "John", LastName: "Doe", Metadata: `{`}, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := decodeGRPCAddAccountRequest(context.Background(), tt.req)
+		if (err != nil) != tt.err || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%+v: got %+v, %v, want %+v", tt.req, got, err, tt.want)
+		}
+	}
+}
+
+func TestEncodeGRPCAccountResponse(t *testing.T) {
+	a := New(1, "John", "Doe")
+	a.ID, a.ExternalID, a.Metadata = 7, "crm-1", metadata.Metadata{"source": "web"}
+	got, err := encodeGRPCAccountResponse(context.Background(), getAccountResponse{Account: a})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &pb.AccountReply{Account: &pb.Account{Id: 7, CustomerId: 1, FirstName: "John", LastName: "Doe", Status: "active", Tier: "standard", ExternalId: "crm-1", Metadata: `{"source":"web"}`}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if _, err := encodeGRPCAccountResponse(context.Background(), addAccountResponse{Err: ErrNotFound{ID: 7}}); err != (ErrNotFound{ID: 7}) {
+		t.Errorf("failed response: got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package account
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	for _, c := range []*Cursor{{ID: 42}, {ID: 7, Value: "O'Brien: Jr"}} {
+		got, err := decodeCursor(encodeCursor(c))
+		if err != nil || *got != *c {
+			t.Errorf("got %+v, %v, want %+v", got, err, c)
+		}
+	}
+	if s := encodeCursor(nil); s != "" {
+		t.Errorf("nil cursor: got %q", s)
+	}
+	for _, s := range []string{"!", "MTIz", "eDpEb2U"} {
+		if _, err := decodeCursor(s); err == nil {
+			t.Errorf("%q: got no error", s)
+		}
+	}
+}
+
+func TestDecodeListAccountsRequest(t *testing.T) {
+	c := &Cursor{ID: 3, Value: "Doe"}
+	tests := []struct {
+		query string
+		want  ListFilter
+		err   bool
+	}{
+		{"", ListFilter{}, false},
+		{"limit=20&sort=last_name&order=desc&cursor=" + encodeCursor(c), ListFilter{Limit: 20, Sort: SortByLastName, Order: OrderDesc, After: c}, false},
+		{"q=+jo+&customer_id=5&total=true", ListFilter{Query: "jo", CustomerID: 5, WithTotal: true}, false},
+		{"limit=-1", ListFilter{}, true},
+		{"sort=email", ListFilter{}, true},
+		{"order=up", ListFilter{}, true},
+		{"cursor=x", ListFilter{}, true},
+		{"customer_id=bob", ListFilter{}, true},
+		{"total=maybe", ListFilter{}, true},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/?"+tt.query, nil)
+		got, err := decodeListAccountsRequest(context.Background(), r)
+		if (err != nil) != tt.err {
+			t.Errorf("%q: got error %v", tt.query, err)
+			continue
+		}
+		if want := (listAccountsRequest{Filter: tt.want}); err == nil && !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: got %+v, want %+v", tt.query, got, want)
+		}
+	}
+}
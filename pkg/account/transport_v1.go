@@ -0,0 +1,254 @@
+package account
+
+import (
+	"coins/pkg/auth"
+	"coins/pkg/metadata"
+	"coins/pkg/money"
+	"coins/pkg/problem"
+	"coins/pkg/ratelimit"
+	"coins/pkg/response"
+	"coins/pkg/validation"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+)
+
+// MakeHandlerV1 build handlers for account transport of API v1. v1 is frozen: it always answers with legacy
+// statuses(201 for every success) and `{"error": "..."}` bodies and decodes requests with its own decoders,
+// so changes of v2 don't leak into it
+func MakeHandlerV1(as Service, authn auth.Authenticator, rl *ratelimit.Limiter, logger log.Logger) http.Handler {
+	const prefix = "/account/v1/"
+	enc := response.NewEncoder(logger, true, http.StatusCreated)
+	opts := []kithttp.ServerOption{
+		kithttp.ServerBefore(kithttp.PopulateRequestContext, problem.PopulateRequestID, auth.HTTPToContext),
+		kithttp.ServerAfter(problem.SetRequestIDHeader),
+		kithttp.ServerErrorEncoder(enc.EncodeError),
+	}
+	read, write := rl.Wrap(auth.Require(authn, auth.ScopeAccountsRead)), rl.Wrap(auth.Require(authn, auth.ScopeAccountsWrite))
+
+	addAccountHandler := kithttp.NewServer(
+		write(validation.Middleware(makeAddAccountEndpoint(as))),
+		decodeAddAccountRequestV1,
+		enc.CreatedIn(prefix),
+		opts...,
+	)
+
+	getAccountHandler := kithttp.NewServer(
+		read(validation.Middleware(makeGetAccountEndpoint(as))),
+		decodeGetAccountRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	listAccountsHandler := kithttp.NewServer(
+		read(validation.Middleware(makeListAccountsEndpoint(as))),
+		decodeListAccountsRequestV1,
+		enc.OK(),
+		opts...,
+	)
+
+	updateAccountHandler := kithttp.NewServer(
+		write(validation.Middleware(makeUpdateAccountEndpoint(as))),
+		decodeUpdateAccountRequestV1,
+		enc.OK(),
+		opts...,
+	)
+
+	freezeAccountHandler := kithttp.NewServer(
+		write(validation.Middleware(makeSetStatusEndpoint(as.Freeze))),
+		decodeGetAccountRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	unfreezeAccountHandler := kithttp.NewServer(
+		write(validation.Middleware(makeSetStatusEndpoint(as.Unfreeze))),
+		decodeGetAccountRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	closeAccountHandler := kithttp.NewServer(
+		write(validation.Middleware(makeCloseAccountEndpoint(as))),
+		decodeCloseAccountRequestV1,
+		enc.OK(),
+		opts...,
+	)
+
+	reopenAccountHandler := kithttp.NewServer(
+		write(validation.Middleware(makeSetStatusEndpoint(as.Reopen))),
+		decodeGetAccountRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	getLimitsHandler := kithttp.NewServer(
+		read(validation.Middleware(makeGetLimitsEndpoint(as))),
+		decodeGetAccountRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	setLimitsHandler := kithttp.NewServer(
+		write(validation.Middleware(makeSetLimitsEndpoint(as))),
+		decodeSetLimitsRequestV1,
+		enc.OK(),
+		opts...,
+	)
+
+	r := mux.NewRouter()
+
+	r.Handle(prefix, addAccountHandler).Methods("POST")
+	r.Handle(prefix+"{id}", getAccountHandler).Methods("GET")
+	r.Handle(prefix, listAccountsHandler).Methods("GET")
+	r.Handle(prefix+"{id}", updateAccountHandler).Methods("PATCH")
+	r.Handle(prefix+"{id}/freeze", freezeAccountHandler).Methods("POST")
+	r.Handle(prefix+"{id}/unfreeze", unfreezeAccountHandler).Methods("POST")
+	r.Handle(prefix+"{id}/close", closeAccountHandler).Methods("POST")
+	r.Handle(prefix+"{id}/reopen", reopenAccountHandler).Methods("POST")
+	r.Handle(prefix+"{id}/limits", getLimitsHandler).Methods("GET")
+	r.Handle(prefix+"{id}/limits/{currency}", setLimitsHandler).Methods("PUT")
+
+	return r
+}
+
+func decodeAddAccountRequestV1(_ context.Context, r *http.Request) (interface{}, error) {
+	var body struct {
+		CustomerID int64             `json:"customer_id"`
+		FirstName  string            `json:"first_name"`
+		LastName   string            `json:"last_name"`
+		ExternalID string            `json:"external_id"`
+		Tier       Tier              `json:"tier"`
+		Metadata   metadata.Metadata `json:"metadata"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	return addAccountRequest{
+		CustomerID: body.CustomerID,
+		FirstName:  body.FirstName,
+		LastName:   body.LastName,
+		ExternalID: body.ExternalID,
+		Tier:       body.Tier,
+		Metadata:   body.Metadata,
+	}, nil
+}
+
+func decodeUpdateAccountRequestV1(ctx context.Context, r *http.Request) (interface{}, error) {
+	req, err := decodeGetAccountRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		FirstName *string `json:"first_name"`
+		LastName  *string `json:"last_name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return updateAccountRequest{
+		ID:        req.(getAccountRequest).ID,
+		FirstName: body.FirstName,
+		LastName:  body.LastName,
+	}, nil
+}
+
+func decodeCloseAccountRequestV1(ctx context.Context, r *http.Request) (interface{}, error) {
+	req, err := decodeGetAccountRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		SweepTo int64 `json:"sweep_to"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return closeAccountRequest{ID: req.(getAccountRequest).ID, SweepTo: body.SweepTo}, nil
+}
+
+func decodeSetLimitsRequestV1(ctx context.Context, r *http.Request) (interface{}, error) {
+	req, err := decodeGetAccountRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	currency, err := money.ParseCurrency(mux.Vars(r)["currency"])
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Overdraft      *money.Amount `json:"overdraft"`
+		MinBalance     *money.Amount `json:"min_balance"`
+		MaxTransaction *money.Amount `json:"max_transaction"`
+		DailyOutflow   *money.Amount `json:"daily_outflow_cap"`
+		MonthlyOutflow *money.Amount `json:"monthly_outflow_cap"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return setLimitsRequest{
+		ID: req.(getAccountRequest).ID,
+		Limits: Limits{
+			Currency:       currency,
+			Overdraft:      body.Overdraft,
+			MinBalance:     body.MinBalance,
+			MaxTransaction: body.MaxTransaction,
+			DailyOutflow:   body.DailyOutflow,
+			MonthlyOutflow: body.MonthlyOutflow,
+		},
+	}, nil
+}
+
+func decodeListAccountsRequestV1(_ context.Context, r *http.Request) (interface{}, error) {
+	var (
+		f   ListFilter
+		err error
+	)
+	q := r.URL.Query()
+	if v := q.Get("limit"); v != "" {
+		if f.Limit, err = strconv.Atoi(v); err != nil || f.Limit <= 0 {
+			return nil, errBadRequest{Msg: fmt.Sprintf("limit param must be positive int")}
+		}
+	}
+	switch sf := SortField(q.Get("sort")); sf {
+	case "", SortByID, SortByFirstName, SortByLastName:
+		f.Sort = sf
+	default:
+		return nil, errBadRequest{Msg: fmt.Sprintf("sort param must be %s, %s or %s", SortByID, SortByFirstName, SortByLastName)}
+	}
+	switch o := Order(q.Get("order")); o {
+	case "", OrderAsc, OrderDesc:
+		f.Order = o
+	default:
+		return nil, errBadRequest{Msg: fmt.Sprintf("order param must be %s or %s", OrderAsc, OrderDesc)}
+	}
+	if v := q.Get("cursor"); v != "" {
+		if f.After, err = decodeCursor(v); err != nil {
+			return nil, errBadRequest{Msg: fmt.Sprintf("cursor param is invalid")}
+		}
+	}
+	f.Query = strings.TrimSpace(q.Get("q"))
+	if v := q.Get("customer_id"); v != "" {
+		if f.CustomerID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, errBadRequest{Msg: fmt.Sprintf("customer_id param must be int")}
+		}
+	}
+	f.ExternalID = q.Get("external_id")
+	f.Metadata = metadata.FilterFromQuery(q)
+	if v := q.Get("total"); v != "" {
+		if f.WithTotal, err = strconv.ParseBool(v); err != nil {
+			return nil, errBadRequest{Msg: fmt.Sprintf("total param must be bool")}
+		}
+	}
+	return listAccountsRequest{Filter: f}, nil
+}
@@ -0,0 +1,68 @@
+package account
+
+import "coins/pkg/validation"
+
+// column sizes
+const (
+	nameMaxLength       = 50
+	externalIDMaxLength = 255
+)
+
+func (r addAccountRequest) Validate() error {
+	var vv validation.Violations
+	if r.CustomerID < 0 {
+		vv.Add("customer_id", validation.CodeInvalid, "must be positive ID")
+	}
+	vv.Name("first_name", r.FirstName, nameMaxLength)
+	vv.Name("last_name", r.LastName, nameMaxLength)
+	vv.MaxLength("external_id", r.ExternalID, externalIDMaxLength)
+	if r.Tier != "" && !r.Tier.Valid() {
+		vv.Add("tier", validation.CodeInvalid, "must be one of standard, premium")
+	}
+	vv.Metadata("metadata", r.Metadata)
+	return vv.Err()
+}
+
+func (r updateAccountRequest) Validate() error {
+	var vv validation.Violations
+	if r.FirstName != nil {
+		vv.Name("first_name", *r.FirstName, nameMaxLength)
+	}
+	if r.LastName != nil {
+		vv.Name("last_name", *r.LastName, nameMaxLength)
+	}
+	return vv.Err()
+}
+
+func (r closeAccountRequest) Validate() error {
+	var vv validation.Violations
+	if r.SweepTo < 0 {
+		vv.Add("sweep_to", validation.CodeInvalid, "must be positive ID")
+	}
+	vv.DifferentAccounts("sweep_to", r.ID, r.SweepTo)
+	return vv.Err()
+}
+
+func (r setLimitsRequest) Validate() error {
+	var vv validation.Violations
+	l := r.Limits
+	if l.Overdraft != nil {
+		vv.NotNegative("overdraft", *l.Overdraft)
+	}
+	if l.MinBalance != nil {
+		vv.NotNegative("min_balance", *l.MinBalance)
+		if l.Overdraft != nil {
+			vv.Add("min_balance", validation.CodeInvalid, "can't be set together with overdraft")
+		}
+	}
+	if l.MaxTransaction != nil {
+		vv.Positive("max_transaction", *l.MaxTransaction)
+	}
+	if l.DailyOutflow != nil {
+		vv.Positive("daily_outflow_cap", *l.DailyOutflow)
+	}
+	if l.MonthlyOutflow != nil {
+		vv.Positive("monthly_outflow_cap", *l.MonthlyOutflow)
+	}
+	return vv.Err()
+}
@@ -0,0 +1,65 @@
+package account
+
+import (
+	"coins/pkg/metadata"
+	"coins/pkg/money"
+	"coins/pkg/validation"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// violations return `field:code` of every violation of err
+func violations(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	verr, ok := err.(validation.Error)
+	if !ok {
+		t.Fatalf("got %T error %v, want validation.Error", err, err)
+	}
+	var vv []string
+	for _, v := range verr.Violations {
+		vv = append(vv, v.Field+":"+v.Code)
+	}
+	return vv
+}
+
+func TestValidate(t *testing.T) {
+	name, blank, long := "Alice", " ", strings.Repeat("a", nameMaxLength+1)
+	zero, positive, negative := money.Amount(0), money.Amount(10000), money.Amount(-1)
+	tests := []struct {
+		name string
+		req  validation.Validator
+		want []string
+	}{
+		{"account", addAccountRequest{FirstName: "Alice", LastName: "Smith"}, nil},
+		{"account of customer", addAccountRequest{CustomerID: 1, FirstName: "Alice", LastName: "Smith"}, nil},
+		{"account without names", addAccountRequest{}, []string{"first_name:required", "last_name:required"}},
+		{"negative customer", addAccountRequest{CustomerID: -1, FirstName: "Alice", LastName: "Smith"}, []string{"customer_id:invalid"}},
+		{"long name", addAccountRequest{FirstName: long, LastName: "Smith"}, []string{"first_name:too_long"}},
+		{"long external_id", addAccountRequest{FirstName: "Alice", LastName: "Smith", ExternalID: strings.Repeat("x", externalIDMaxLength+1)}, []string{"external_id:too_long"}},
+		{"premium tier", addAccountRequest{FirstName: "Alice", LastName: "Smith", Tier: TierPremium}, nil},
+		{"unknown tier", addAccountRequest{FirstName: "Alice", LastName: "Smith", Tier: "gold"}, []string{"tier:invalid"}},
+		{"bad metadata", addAccountRequest{FirstName: "Alice", LastName: "Smith", Metadata: metadata.Metadata{"": 1}}, []string{"metadata:invalid"}},
+		{"update nothing", updateAccountRequest{ID: 1}, nil},
+		{"update name", updateAccountRequest{ID: 1, FirstName: &name}, nil},
+		{"blank name", updateAccountRequest{ID: 1, FirstName: &blank, LastName: &long}, []string{"first_name:required", "last_name:too_long"}},
+		{"close", closeAccountRequest{ID: 1}, nil},
+		{"close and sweep", closeAccountRequest{ID: 1, SweepTo: 2}, nil},
+		{"sweep to itself", closeAccountRequest{ID: 1, SweepTo: 1}, []string{"sweep_to:same_account"}},
+		{"negative sweep_to", closeAccountRequest{ID: 1, SweepTo: -1}, []string{"sweep_to:invalid"}},
+		{"no limits", setLimitsRequest{ID: 1, Limits: Limits{Currency: "EUR"}}, nil},
+		{"limits", setLimitsRequest{ID: 1, Limits: Limits{Currency: "EUR", Overdraft: &zero, MaxTransaction: &positive, DailyOutflow: &positive, MonthlyOutflow: &positive}}, nil},
+		{"min balance", setLimitsRequest{ID: 1, Limits: Limits{Currency: "EUR", MinBalance: &positive}}, nil},
+		{"negative floor", setLimitsRequest{ID: 1, Limits: Limits{Currency: "EUR", Overdraft: &negative}}, []string{"overdraft:negative"}},
+		{"overdraft and min balance", setLimitsRequest{ID: 1, Limits: Limits{Currency: "EUR", Overdraft: &positive, MinBalance: &negative}}, []string{"min_balance:negative", "min_balance:invalid"}},
+		{"zero caps", setLimitsRequest{ID: 1, Limits: Limits{Currency: "EUR", MaxTransaction: &zero, DailyOutflow: &zero, MonthlyOutflow: &negative}}, []string{"max_transaction:not_positive", "daily_outflow_cap:not_positive", "monthly_outflow_cap:not_positive"}},
+	}
+	for _, tt := range tests {
+		if got := violations(t, tt.req.Validate()); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
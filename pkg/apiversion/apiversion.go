@@ -0,0 +1,53 @@
+package apiversion
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// supported API versions
+const (
+	V1 = "v1"
+	V2 = "v2"
+)
+
+// usage number of requests served by each API version, served to admins by MakeHandler
+var usage = struct {
+	sync.Mutex
+	requests map[string]int64
+}{requests: make(map[string]int64)}
+
+// Count wraps handler of API version, count every request it serves
+func Count(version string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		usage.Lock()
+		usage.requests[version]++
+		usage.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Usage return number of requests served by each API version since start
+func Usage() map[string]int64 {
+	usage.Lock()
+	defer usage.Unlock()
+	requests := make(map[string]int64, len(usage.requests))
+	for v, n := range usage.requests {
+		requests[v] = n
+	}
+	return requests
+}
+
+// Deprecate wraps handler of deprecated routes under prefix, every response gets Deprecation header and
+// Link to the same resource under successor prefix, e.g. "/account/v1/" and "/v2/account/"
+func Deprecate(prefix, successor string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			link := successor + strings.TrimPrefix(r.URL.Path, prefix)
+			w.Header().Set("Link", "<"+link+`>; rel="successor-version"`)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,49 @@
+package apiversion
+
+import (
+	"coins/pkg/auth"
+	"coins/pkg/problem"
+	"coins/pkg/ratelimit"
+	"coins/pkg/response"
+	"context"
+	"net/http"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+)
+
+// MakeHandler build handler serving usage of API versions to admins
+func MakeHandler(authn auth.Authenticator, rl *ratelimit.Limiter, logger log.Logger) http.Handler {
+	enc := response.NewEncoder(logger, false, 0)
+	opts := []kithttp.ServerOption{
+		kithttp.ServerBefore(kithttp.PopulateRequestContext, problem.PopulateRequestID, auth.HTTPToContext),
+		kithttp.ServerAfter(problem.SetRequestIDHeader),
+		kithttp.ServerErrorEncoder(enc.EncodeError),
+	}
+	admin := rl.Wrap(auth.Require(authn, auth.ScopeAdmin))
+
+	usageHandler := kithttp.NewServer(
+		admin(makeUsageEndpoint()),
+		kithttp.NopRequestDecoder,
+		enc.OK(),
+		opts...,
+	)
+
+	r := mux.NewRouter()
+
+	r.Handle("/admin/v1/usage", usageHandler).Methods("GET")
+
+	return r
+}
+
+func makeUsageEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		return usageResponse{Requests: Usage()}, nil
+	}
+}
+
+type usageResponse struct {
+	Requests map[string]int64 `json:"requests"`
+}
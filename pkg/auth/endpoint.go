@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+func makeListKeysEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		keys, err := s.List(ctx)
+		return listKeysResponse{Keys: keys, Err: err}, err
+	}
+}
+
+type listKeysResponse struct {
+	Keys []*Key `json:"keys"`
+	Err  error  `json:"-"`
+}
+
+func (r listKeysResponse) Failed() error { return r.Err }
+
+func makeIssueKeyEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(issueKeyRequest)
+		k, token, err := s.Issue(ctx, req.Name, req.Scopes)
+		return issueKeyResponse{Key: k, Token: token, Err: err}, err
+	}
+}
+
+type issueKeyRequest struct {
+	Name   string
+	Scopes []Scope
+}
+
+type issueKeyResponse struct {
+	Key *Key `json:"key,omitempty"`
+	// Token is returned only once, when key is issued
+	Token string `json:"token,omitempty"`
+	Err   error  `json:"-"`
+}
+
+func (r issueKeyResponse) Failed() error { return r.Err }
+
+func makeRevokeKeyEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(revokeKeyRequest)
+		k, err := s.Revoke(ctx, req.ID)
+		return keyResponse{Key: k, Err: err}, err
+	}
+}
+
+type revokeKeyRequest struct {
+	ID int64
+}
+
+type keyResponse struct {
+	Key *Key  `json:"key,omitempty"`
+	Err error `json:"-"`
+}
+
+func (r keyResponse) Failed() error { return r.Err }
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"coins/pkg/problem"
+	"fmt"
+)
+
+// ErrUnauthenticated - raised when API key is missing, unknown or revoked
+type ErrUnauthenticated struct{}
+
+func (e ErrUnauthenticated) Error() string {
+	return "API key or bearer token is missing, invalid or revoked"
+}
+
+// Code implements problem.Coder
+func (e ErrUnauthenticated) Code() string {
+	return problem.CodeUnauthenticated
+}
+
+// ErrForbidden - raised when API key isn't granted scope required by endpoint
+type ErrForbidden struct {
+	Scope Scope
+}
+
+func (e ErrForbidden) Error() string {
+	return fmt.Sprintf("API key isn't granted %s scope", e.Scope)
+}
+
+// Code implements problem.Coder
+func (e ErrForbidden) Code() string {
+	return problem.CodeForbidden
+}
+
+// ErrKeyNotFound - raised when API key not found
+type ErrKeyNotFound struct {
+	ID int64
+}
+
+func (e ErrKeyNotFound) Error() string {
+	return fmt.Sprintf("API key with ID %d not found", e.ID)
+}
+
+// Code implements problem.Coder
+func (e ErrKeyNotFound) Code() string {
+	return problem.CodeAPIKeyNotFound
+}
+
+// ErrNotOwner - raised when bearer token is bound to another account than the one request acts on
+type ErrNotOwner struct {
+	AccountID int64
+}
+
+func (e ErrNotOwner) Error() string {
+	return fmt.Sprintf("token isn't allowed to act on account %d", e.AccountID)
+}
+
+// Code implements problem.Coder
+func (e ErrNotOwner) Code() string {
+	return problem.CodeForbidden
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/pkg/errors"
+)
+
+// Claims of end-user bearer token. Subject is ID of the account the token is bound to,
+// Scope is space-separated list of granted scopes
+type Claims struct {
+	Scope string `json:"scope,omitempty"`
+	jwt.StandardClaims
+}
+
+// Has return true when token is granted scope. Admin scope is never granted to bearer tokens, only API keys have it
+func (c *Claims) Has(scope Scope) bool {
+	if scope == ScopeAdmin {
+		return false
+	}
+	for _, s := range strings.Fields(c.Scope) {
+		if Scope(s) == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// AccountID return ID of account token is bound to
+func (c *Claims) AccountID() int64 {
+	id, _ := strconv.ParseInt(c.Subject, 10, 64)
+	return id
+}
+
+// Valid implements jwt.Claims, token must expire and be bound to account
+func (c *Claims) Valid() error {
+	if err := c.StandardClaims.Valid(); err != nil {
+		return err
+	}
+	if c.ExpiresAt == 0 {
+		return errors.New("token has no expiration time")
+	}
+	if c.AccountID() <= 0 {
+		return errors.New("token subject must be account ID")
+	}
+	return nil
+}
+
+// Verifier verify bearer tokens signed with HMAC secret(HS256) or RSA private key(RS256)
+type Verifier struct {
+	secret    []byte
+	publicKey *rsa.PublicKey
+}
+
+// MinSecretLength shortest HMAC secret accepted, HS256 secret must be at least as long as the hash
+const MinSecretLength = 32
+
+// NewVerifier load HMAC secret and PEM encoded RSA public key from files, empty path disables the algorithm.
+// Secret shorter than MinSecretLength after trimming spaces is rejected
+func NewVerifier(secretFile, publicKeyFile string) (*Verifier, error) {
+	v := &Verifier{}
+	if secretFile != "" {
+		b, err := ioutil.ReadFile(secretFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read JWT secret")
+		}
+		secret := strings.TrimSpace(string(b))
+		if len(secret) < MinSecretLength {
+			return nil, errors.Errorf("JWT secret must be at least %d bytes", MinSecretLength)
+		}
+		v.secret = []byte(secret)
+	}
+	if publicKeyFile != "" {
+		b, err := ioutil.ReadFile(publicKeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read JWT public key")
+		}
+		if v.publicKey, err = jwt.ParseRSAPublicKeyFromPEM(b); err != nil {
+			return nil, errors.Wrap(err, "unable to parse JWT public key")
+		}
+	}
+	return v, nil
+}
+
+// Verify parse token and check its signature and claims, raise ErrUnauthenticated for any invalid token
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, v.key)
+	if err != nil {
+		return nil, ErrUnauthenticated{}
+	}
+	return claims, nil
+}
+
+// key select verification key by signing method of token, so HMAC secret is never used to verify RSA token
+// and the other way around
+func (v *Verifier) key(t *jwt.Token) (interface{}, error) {
+	switch t.Method {
+	case jwt.SigningMethodHS256:
+		if len(v.secret) > 0 {
+			return v.secret, nil
+		}
+	case jwt.SigningMethodRS256:
+		if v.publicKey != nil {
+			return v.publicKey, nil
+		}
+	}
+	return nil, errors.Errorf("unexpected signing method %v", t.Header["alg"])
+}
+
+// ClaimsFromContext return claims of bearer token the request was authenticated with
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	c, ok := ctx.Value(claimsKey{}).(*Claims)
+	return c, ok
+}
+
+// CheckOwner return ErrNotOwner when request was authenticated with bearer token bound to another account.
+// Requests authenticated with API key act on any account
+func CheckOwner(ctx context.Context, accountID int64) error {
+	c, ok := ClaimsFromContext(ctx)
+	if !ok || c.AccountID() == accountID {
+		return nil
+	}
+	return ErrNotOwner{AccountID: accountID}
+}
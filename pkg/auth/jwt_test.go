@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func writeFile(t *testing.T, dir, name string, b []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "jwt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func newRSAKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, c *Claims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, c).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func validClaims(scope string) *Claims {
+	return &Claims{
+		Scope:          scope,
+		StandardClaims: jwt.StandardClaims{Subject: "7", ExpiresAt: time.Now().Add(time.Hour).Unix()},
+	}
+}
+
+func TestNewVerifierSecret(t *testing.T) {
+	dir := tempDir(t)
+	tests := []struct {
+		name   string
+		secret string
+		ok     bool
+	}{
+		{"empty", "", false},
+		{"whitespace", " \n\t ", false},
+		{"short", "secret", false},
+		{"padded short", "  " + testSecret[:MinSecretLength-1] + "\n", false},
+		{"long enough", testSecret + "\n", true},
+	}
+	for _, tt := range tests {
+		_, err := NewVerifier(writeFile(t, dir, "secret", []byte(tt.secret)), "")
+		if (err == nil) != tt.ok {
+			t.Errorf("%s secret: got error %v", tt.name, err)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	dir := tempDir(t)
+	rsaKey, pub := newRSAKey(t)
+	otherKey, _ := newRSAKey(t)
+
+	hsOnly, err := NewVerifier(writeFile(t, dir, "secret", []byte(testSecret)), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsOnly, err := NewVerifier("", writeFile(t, dir, "key.pem", pub))
+	if err != nil {
+		t.Fatal(err)
+	}
+	none, err := NewVerifier("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired := validClaims("payments:read")
+	expired.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	noExp := validClaims("payments:read")
+	noExp.ExpiresAt = 0
+	noSubject := validClaims("payments:read")
+	noSubject.Subject = "alice"
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims("payments:read")).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		v     *Verifier
+		token string
+		ok    bool
+	}{
+		{"HS256 with secret", hsOnly, sign(t, jwt.SigningMethodHS256, []byte(testSecret), validClaims("payments:read")), true},
+		{"HS256 with wrong secret", hsOnly, sign(t, jwt.SigningMethodHS256, []byte(testSecret+"x"), validClaims("payments:read")), false},
+		{"HS512 with secret", hsOnly, sign(t, jwt.SigningMethodHS512, []byte(testSecret), validClaims("payments:read")), false},
+		{"RS256 to HS256 key", hsOnly, sign(t, jwt.SigningMethodRS256, rsaKey, validClaims("payments:read")), false},
+		{"RS256 with public key", rsOnly, sign(t, jwt.SigningMethodRS256, rsaKey, validClaims("payments:read")), true},
+		{"RS256 of other key", rsOnly, sign(t, jwt.SigningMethodRS256, otherKey, validClaims("payments:read")), false},
+		// public key used as HMAC secret must not verify, the classic algorithm confusion
+		{"HS256 signed with public key", rsOnly, sign(t, jwt.SigningMethodHS256, pub, validClaims("payments:read")), false},
+		{"HS256 without secret", none, sign(t, jwt.SigningMethodHS256, []byte(""), validClaims("payments:read")), false},
+		{"alg none", hsOnly, unsigned, false},
+		{"expired", hsOnly, sign(t, jwt.SigningMethodHS256, []byte(testSecret), expired), false},
+		{"without exp", hsOnly, sign(t, jwt.SigningMethodHS256, []byte(testSecret), noExp), false},
+		{"subject isn't account", hsOnly, sign(t, jwt.SigningMethodHS256, []byte(testSecret), noSubject), false},
+		{"garbage", hsOnly, "not.a.token", false},
+	}
+	for _, tt := range tests {
+		c, err := tt.v.Verify(tt.token)
+		if tt.ok && (err != nil || c.AccountID() != 7) {
+			t.Errorf("%s: got %v, %v", tt.name, c, err)
+		}
+		if !tt.ok && err != (ErrUnauthenticated{}) {
+			t.Errorf("%s: got error %v, want ErrUnauthenticated", tt.name, err)
+		}
+	}
+}
+
+func TestClaimsHas(t *testing.T) {
+	c := validClaims("payments:read admin")
+	if !c.Has(ScopePaymentsRead) {
+		t.Error("granted scope isn't accepted")
+	}
+	if c.Has(ScopePaymentsWrite) {
+		t.Error("admin in token grants other scopes")
+	}
+	if c.Has(ScopeAdmin) {
+		t.Error("admin scope is granted to bearer token")
+	}
+}
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	"google.golang.org/grpc/metadata"
+)
+
+// APIKeyHeader header carrying API key token
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyMetadata gRPC metadata key carrying API key token
+const APIKeyMetadata = "x-api-key"
+
+// BearerMetadata gRPC metadata key carrying bearer token
+const BearerMetadata = "authorization"
+
+type tokenKey struct{}
+
+type keyKey struct{}
+
+type bearerKey struct{}
+
+type claimsKey struct{}
+
+// bearer return token of "Bearer <token>" authorization value
+func bearer(authorization string) string {
+	const prefix = "bearer "
+	if len(authorization) > len(prefix) && strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return authorization[len(prefix):]
+	}
+	return ""
+}
+
+// HTTPToContext implements kithttp.RequestFunc, put API key token from header and bearer token from
+// Authorization header into context
+func HTTPToContext(ctx context.Context, r *http.Request) context.Context {
+	ctx = context.WithValue(ctx, bearerKey{}, bearer(r.Header.Get("Authorization")))
+	return context.WithValue(ctx, tokenKey{}, r.Header.Get(APIKeyHeader))
+}
+
+// GRPCToContext implements kitgrpc.ServerRequestFunc, put API key token and bearer token from metadata into context
+func GRPCToContext(ctx context.Context, md metadata.MD) context.Context {
+	if v := md.Get(BearerMetadata); len(v) > 0 {
+		ctx = context.WithValue(ctx, bearerKey{}, bearer(v[0]))
+	}
+	var token string
+	if v := md.Get(APIKeyMetadata); len(v) > 0 {
+		token = v[0]
+	}
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// KeyFromContext return API key authenticated for the request
+func KeyFromContext(ctx context.Context) (*Key, bool) {
+	k, ok := ctx.Value(keyKey{}).(*Key)
+	return k, ok
+}
+
+// Require return endpoint middleware authenticating API key token from context and checking the key is granted
+// scope. Raise ErrUnauthenticated or ErrForbidden before the endpoint is called
+func Require(a Authenticator, scope Scope) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			ctx, err := authenticateKey(ctx, a, scope)
+			if err != nil {
+				return nil, err
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// RequireOwner return endpoint middleware like Require, which also accepts bearer token granted scope.
+// Claims of the token are put into context, endpoint must check the token owns the account with CheckOwner
+func RequireOwner(a Authenticator, scope Scope) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			token, _ := ctx.Value(bearerKey{}).(string)
+			if key, _ := ctx.Value(tokenKey{}).(string); key != "" || token == "" {
+				ctx, err := authenticateKey(ctx, a, scope)
+				if err != nil {
+					return nil, err
+				}
+				return next(ctx, request)
+			}
+			c, err := a.VerifyToken(token)
+			if err != nil {
+				return nil, err
+			}
+			if !c.Has(scope) {
+				return nil, ErrForbidden{Scope: scope}
+			}
+			return next(context.WithValue(ctx, claimsKey{}, c), request)
+		}
+	}
+}
+
+func authenticateKey(ctx context.Context, a Authenticator, scope Scope) (context.Context, error) {
+	token, _ := ctx.Value(tokenKey{}).(string)
+	if token == "" {
+		return ctx, ErrUnauthenticated{}
+	}
+	k, err := a.Authenticate(ctx, token)
+	if err != nil {
+		return ctx, err
+	}
+	if !k.Has(scope) {
+		return ctx, ErrForbidden{Scope: scope}
+	}
+	return context.WithValue(ctx, keyKey{}, k), nil
+}
+
+// Identity return identity of client authenticated for the request: ID of API key or account bound to bearer token
+func Identity(ctx context.Context) (string, bool) {
+	if k, ok := KeyFromContext(ctx); ok {
+		return "key:" + strconv.FormatInt(k.ID, 10), true
+	}
+	if c, ok := ClaimsFromContext(ctx); ok {
+		return "account:" + c.Subject, true
+	}
+	return "", false
+}
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Scope permission granted to API key
+type Scope string
+
+// scopes, payouts:write is granted only to the payout provider reporting withdrawal results, admin grants
+// every other scope and management of API keys
+const (
+	ScopeAccountsRead  Scope = "accounts:read"
+	ScopeAccountsWrite Scope = "accounts:write"
+	ScopePaymentsRead  Scope = "payments:read"
+	ScopePaymentsWrite Scope = "payments:write"
+	ScopePayoutsWrite  Scope = "payouts:write"
+	ScopeAdmin         Scope = "admin"
+)
+
+var scopes = map[Scope]bool{
+	ScopeAccountsRead:  true,
+	ScopeAccountsWrite: true,
+	ScopePaymentsRead:  true,
+	ScopePaymentsWrite: true,
+	ScopePayoutsWrite:  true,
+	ScopeAdmin:         true,
+}
+
+// tokenPrefix marks API keys, so leaked keys are easy to find in logs and repositories
+const tokenPrefix = "coins"
+
+// Key model of API key. Token is `coins_<prefix>_<secret>`, only Prefix and SHA-256 Hash of the token are
+// stored, so the token is shown to client once when the key is issued
+type Key struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Scopes    []Scope    `json:"scopes"`
+	Hash      string     `json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// New create key with random token, return key and its token
+func New(name string, scopes []Scope) (*Key, string, error) {
+	prefix, err := randomHex(4)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, "", err
+	}
+	token := tokenPrefix + "_" + prefix + "_" + secret
+	return &Key{
+		Name:      name,
+		Prefix:    prefix,
+		Scopes:    scopes,
+		Hash:      hashToken(token),
+		CreatedAt: time.Now().UTC(),
+	}, token, nil
+}
+
+// Has return true when key is granted scope directly or by admin scope
+func (k *Key) Has(scope Scope) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// Revoked return true when key can't be used anymore
+func (k *Key) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// parseToken return prefix of token or false when token isn't API key
+func parseToken(token string) (string, bool) {
+	parts := strings.Split(token, "_")
+	if len(parts) != 3 || parts[0] != tokenPrefix || parts[1] == "" || parts[2] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"time"
+)
+
+// Repository interface
+type Repository interface {
+	List(ctx context.Context) ([]*Key, error)
+	// GetByPrefix return key with token prefix, raise ErrUnauthenticated when there is no such key
+	GetByPrefix(ctx context.Context, prefix string) (*Key, error)
+	Store(context.Context, *Key) (*Key, error)
+	// Revoke set revocation time of key unless it's already revoked, raise ErrKeyNotFound for unknown key
+	Revoke(ctx context.Context, id int64, at time.Time) (*Key, error)
+}
+
+// Authenticator resolve API key or bearer token presented by client
+type Authenticator interface {
+	// Authenticate raise ErrUnauthenticated when token isn't a valid active key
+	Authenticate(ctx context.Context, token string) (*Key, error)
+	// VerifyToken raise ErrUnauthenticated when bearer token is invalid or bearer tokens aren't accepted
+	VerifyToken(token string) (*Claims, error)
+}
+
+// Service interface
+type Service interface {
+	Authenticator
+	List(ctx context.Context) ([]*Key, error)
+	// Issue store new key granted scopes, return key and its token which is never shown again
+	Issue(ctx context.Context, name string, scopes []Scope) (*Key, string, error)
+	Revoke(ctx context.Context, id int64) (*Key, error)
+}
+
+type service struct {
+	repo     Repository
+	rootKey  string
+	verifier *Verifier
+}
+
+// Authenticate return active key of token, root key is accepted as admin without lookup
+func (s *service) Authenticate(ctx context.Context, token string) (*Key, error) {
+	if s.rootKey != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.rootKey)) == 1 {
+		return &Key{Name: "root", Scopes: []Scope{ScopeAdmin}}, nil
+	}
+	prefix, ok := parseToken(token)
+	if !ok {
+		return nil, ErrUnauthenticated{}
+	}
+	k, err := s.repo.GetByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashToken(token)), []byte(k.Hash)) != 1 || k.Revoked() {
+		return nil, ErrUnauthenticated{}
+	}
+	return k, nil
+}
+
+// VerifyToken return claims of valid bearer token
+func (s *service) VerifyToken(token string) (*Claims, error) {
+	if s.verifier == nil {
+		return nil, ErrUnauthenticated{}
+	}
+	return s.verifier.Verify(token)
+}
+
+// List return all keys including revoked ones
+func (s *service) List(ctx context.Context) ([]*Key, error) {
+	return s.repo.List(ctx)
+}
+
+// Issue new key
+func (s *service) Issue(ctx context.Context, name string, scopes []Scope) (*Key, string, error) {
+	k, token, err := New(name, scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	if k, err = s.repo.Store(ctx, k); err != nil {
+		return nil, "", err
+	}
+	return k, token, nil
+}
+
+// Revoke key, revoking already revoked key keeps its revocation time
+func (s *service) Revoke(ctx context.Context, id int64) (*Key, error) {
+	return s.repo.Revoke(ctx, id, time.Now().UTC())
+}
+
+// NewService - build new auth service. rootKey, when not empty, is accepted as admin key to issue the first keys.
+// Bearer tokens are accepted only with verifier
+func NewService(repo Repository, rootKey string, verifier *Verifier) Service {
+	return &service{repo: repo, rootKey: rootKey, verifier: verifier}
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"coins/pkg/problem"
+	"coins/pkg/ratelimit"
+	"coins/pkg/response"
+	"coins/pkg/validation"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-kit/kit/log"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+)
+
+type errBadRequest struct {
+	Msg string
+}
+
+func (e errBadRequest) Error() string {
+	return e.Msg
+}
+
+// Code implements problem.Coder
+func (e errBadRequest) Code() string {
+	return problem.CodeInvalidParameter
+}
+
+// MakeHandler build handlers for API keys administration, every route requires admin scope
+func MakeHandler(s Service, rl *ratelimit.Limiter, logger log.Logger) http.Handler {
+	enc := response.NewEncoder(logger, false, 0)
+	opts := []kithttp.ServerOption{
+		kithttp.ServerBefore(kithttp.PopulateRequestContext, problem.PopulateRequestID, HTTPToContext),
+		kithttp.ServerAfter(problem.SetRequestIDHeader),
+		kithttp.ServerErrorEncoder(enc.EncodeError),
+	}
+	admin := rl.Wrap(Require(s, ScopeAdmin))
+
+	listKeysHandler := kithttp.NewServer(
+		admin(makeListKeysEndpoint(s)),
+		kithttp.NopRequestDecoder,
+		enc.OK(),
+		opts...,
+	)
+
+	issueKeyHandler := kithttp.NewServer(
+		admin(validation.Middleware(makeIssueKeyEndpoint(s))),
+		decodeIssueKeyRequest,
+		enc.Created(),
+		opts...,
+	)
+
+	revokeKeyHandler := kithttp.NewServer(
+		admin(validation.Middleware(makeRevokeKeyEndpoint(s))),
+		decodeRevokeKeyRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	r := mux.NewRouter()
+
+	r.Handle("/admin/v1/keys", listKeysHandler).Methods("GET")
+	r.Handle("/admin/v1/keys", issueKeyHandler).Methods("POST")
+	r.Handle("/admin/v1/keys/{id}/revoke", revokeKeyHandler).Methods("POST")
+
+	return r
+}
+
+func decodeIssueKeyRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var body struct {
+		Name   string  `json:"name"`
+		Scopes []Scope `json:"scopes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return issueKeyRequest{Name: body.Name, Scopes: body.Scopes}, nil
+}
+
+func decodeRevokeKeyRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	idStr, ok := vars["id"]
+	if !ok {
+		return nil, errBadRequest{Msg: fmt.Sprintf("id param required")}
+	}
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return nil, errBadRequest{Msg: fmt.Sprintf("id param must be int")}
+	}
+	return revokeKeyRequest{ID: id}, nil
+}
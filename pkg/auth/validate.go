@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"coins/pkg/validation"
+	"fmt"
+)
+
+// nameMaxLength column size
+const nameMaxLength = 100
+
+func (r issueKeyRequest) Validate() error {
+	var vv validation.Violations
+	vv.Name("name", r.Name, nameMaxLength)
+	if len(r.Scopes) == 0 {
+		vv.Add("scopes", validation.CodeRequired, "at least one scope is required")
+	}
+	for i, s := range r.Scopes {
+		if !scopes[s] {
+			vv.Add(fmt.Sprintf("scopes[%d]", i), validation.CodeInvalid, fmt.Sprintf("unknown scope %q", s))
+		}
+	}
+	return vv.Err()
+}
+
+func (r revokeKeyRequest) Validate() error {
+	var vv validation.Violations
+	vv.ID("id", r.ID)
+	return vv.Err()
+}
@@ -0,0 +1,79 @@
+package customer
+
+import (
+	"coins/pkg/account"
+	"context"
+	"strconv"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+func makeAddCustomerEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(addCustomerRequest)
+		customer, err := s.Store(ctx, req.FirstName, req.LastName, req.Email)
+		return customerResponse{Customer: customer, Err: err}, err
+	}
+}
+
+type addCustomerRequest struct {
+	FirstName string
+	LastName  string
+	Email     string
+}
+
+type customerResponse struct {
+	Customer *Customer `json:"customer,omitempty"`
+	Err      error     `json:"-"`
+}
+
+func (r customerResponse) Failed() error { return r.Err }
+
+func (r customerResponse) Location() string {
+	if r.Customer == nil {
+		return ""
+	}
+	return strconv.FormatInt(r.Customer.ID, 10)
+}
+
+func makeGetCustomerEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getCustomerRequest)
+		customer, err := s.Get(ctx, req.ID)
+		return customerResponse{Customer: customer, Err: err}, err
+	}
+}
+
+type getCustomerRequest struct {
+	ID int64
+}
+
+func makeListAccountsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getCustomerRequest)
+		accounts, err := s.Accounts(ctx, req.ID)
+		return listAccountsResponse{Accounts: accounts, Err: err}, err
+	}
+}
+
+type listAccountsResponse struct {
+	Accounts []*account.Account `json:"accounts,omitempty"`
+	Err      error              `json:"-"`
+}
+
+func (r listAccountsResponse) Failed() error { return r.Err }
+
+func makeGetBalanceEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getCustomerRequest)
+		balances, err := s.Balance(ctx, req.ID)
+		return getBalanceResponse{Balances: balances, Err: err}, err
+	}
+}
+
+type getBalanceResponse struct {
+	Balances []*Balance `json:"balances,omitempty"`
+	Err      error      `json:"-"`
+}
+
+func (r getBalanceResponse) Failed() error { return r.Err }
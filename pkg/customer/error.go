@@ -0,0 +1,20 @@
+package customer
+
+import (
+	"coins/pkg/problem"
+	"fmt"
+)
+
+// ErrNotFound - raised when customer not found
+type ErrNotFound struct {
+	ID int64
+}
+
+func (e ErrNotFound) Error() string {
+	return fmt.Sprintf("customer with ID %d not found", e.ID)
+}
+
+// Code implements problem.Coder
+func (e ErrNotFound) Code() string {
+	return problem.CodeCustomerNotFound
+}
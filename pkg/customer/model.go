@@ -0,0 +1,32 @@
+package customer
+
+import (
+	"coins/pkg/money"
+	"time"
+)
+
+// Customer model, owner of one or more accounts
+type Customer struct {
+	ID        int64     `json:"id"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// New create new customer model
+func New(firstName, lastName, email string) *Customer {
+	return &Customer{
+		FirstName: firstName,
+		LastName:  lastName,
+		Email:     email,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// Balance sum of balances in one currency across all accounts of customer
+type Balance struct {
+	Currency  money.Currency `json:"currency"`
+	Ledger    money.Amount   `json:"ledger"`
+	Available money.Amount   `json:"available"`
+}
@@ -0,0 +1,98 @@
+package customer
+
+import (
+	"coins/pkg/account"
+	"coins/pkg/money"
+	"coins/pkg/payment"
+	"context"
+	"sort"
+)
+
+// Repository interface
+type Repository interface {
+	Get(ctx context.Context, id int64) (*Customer, error)
+	Store(context.Context, *Customer) (*Customer, error)
+}
+
+// Service interface
+type Service interface {
+	Get(ctx context.Context, id int64) (*Customer, error)
+	Store(ctx context.Context, firstName, lastName, email string) (*Customer, error)
+	Accounts(ctx context.Context, id int64) ([]*account.Account, error)
+	Balance(ctx context.Context, id int64) ([]*Balance, error)
+}
+
+type service struct {
+	repo     Repository
+	accounts account.Service
+	payments payment.Service
+}
+
+// Get return Customer with requested id or return ErrNotFound error
+func (s *service) Get(ctx context.Context, id int64) (*Customer, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// Store new customer
+func (s *service) Store(ctx context.Context, firstName, lastName, email string) (*Customer, error) {
+	return s.repo.Store(ctx, New(firstName, lastName, email))
+}
+
+// Accounts return all accounts of customer sorted by ID, raise ErrNotFound for unknown customer
+func (s *service) Accounts(ctx context.Context, id int64) ([]*account.Account, error) {
+	if _, err := s.repo.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	var aa []*account.Account
+	f := account.ListFilter{CustomerID: id, Limit: account.MaxPageSize}
+	for {
+		page, err := s.accounts.List(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+		aa = append(aa, page.Accounts...)
+		if page.Next == nil {
+			return aa, nil
+		}
+		f.After = page.Next
+	}
+}
+
+// Balance return balances of all accounts of customer summed per currency, sorted by currency
+func (s *service) Balance(ctx context.Context, id int64) ([]*Balance, error) {
+	aa, err := s.Accounts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sums := make(map[money.Currency]*Balance)
+	for _, a := range aa {
+		bb, err := s.payments.GetBalance(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range bb {
+			sum, ok := sums[b.Currency]
+			if !ok {
+				sum = &Balance{Currency: b.Currency}
+				sums[b.Currency] = sum
+			}
+			if sum.Ledger, err = sum.Ledger.Add(b.Ledger); err != nil {
+				return nil, err
+			}
+			if sum.Available, err = sum.Available.Add(b.Available); err != nil {
+				return nil, err
+			}
+		}
+	}
+	bb := make([]*Balance, 0, len(sums))
+	for _, b := range sums {
+		bb = append(bb, b)
+	}
+	sort.Slice(bb, func(i, j int) bool { return bb[i].Currency < bb[j].Currency })
+	return bb, nil
+}
+
+// NewService build new Service, accounts and balances of customer are retrieved from account and payment services
+func NewService(repo Repository, as account.Service, ps payment.Service) Service {
+	return &service{repo: repo, accounts: as, payments: ps}
+}
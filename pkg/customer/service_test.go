@@ -0,0 +1,132 @@
+package customer
+
+import (
+	"coins/pkg/account"
+	"coins/pkg/money"
+	"coins/pkg/payment"
+	"context"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// stubRepo keep customers in memory
+type stubRepo map[int64]*Customer
+
+func (r stubRepo) Get(_ context.Context, id int64) (*Customer, error) {
+	c, ok := r[id]
+	if !ok {
+		return nil, ErrNotFound{ID: id}
+	}
+	return c, nil
+}
+
+func (r stubRepo) Store(_ context.Context, c *Customer) (*Customer, error) {
+	c.ID = int64(len(r) + 1)
+	r[c.ID] = c
+	return c, nil
+}
+
+// stubAccounts list accounts of customer one per page
+type stubAccounts struct {
+	account.Service
+	accounts []*account.Account
+}
+
+func (s stubAccounts) List(_ context.Context, f account.ListFilter) (*account.Page, error) {
+	var aa []*account.Account
+	for _, a := range s.accounts {
+		if a.CustomerID == f.CustomerID && (f.After == nil || a.ID > f.After.ID) {
+			aa = append(aa, a)
+		}
+	}
+	if len(aa) == 0 {
+		return &account.Page{}, nil
+	}
+	page := &account.Page{Accounts: aa[:1]}
+	if len(aa) > 1 {
+		page.Next = &account.Cursor{ID: aa[0].ID}
+	}
+	return page, nil
+}
+
+// stubPayments serve balances of accounts
+type stubPayments struct {
+	payment.Service
+	balances map[int64][]*payment.Balance
+}
+
+func (s stubPayments) GetBalance(_ context.Context, a *account.Account) ([]*payment.Balance, error) {
+	return s.balances[a.ID], nil
+}
+
+func testAccount(id, customerID int64) *account.Account {
+	a := account.New(customerID, "John", "Doe")
+	a.ID = id
+	return a
+}
+
+func TestStore(t *testing.T) {
+	repo := stubRepo{}
+	c, err := NewService(repo, nil, nil).Store(context.Background(), "John", "Doe", "john@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ID != 1 || c.FirstName != "John" || c.LastName != "Doe" || c.Email != "john@example.com" || repo[1] != c {
+		t.Errorf("got %+v", c)
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	as := stubAccounts{accounts: []*account.Account{testAccount(1, 1), testAccount(2, 2), testAccount(3, 1), testAccount(4, 1)}}
+	s := NewService(stubRepo{1: {ID: 1}, 2: {ID: 2}, 3: {ID: 3}}, as, nil)
+	tests := []struct {
+		customerID int64
+		want       []int64
+		err        error
+	}{
+		{1, []int64{1, 3, 4}, nil},
+		{2, []int64{2}, nil},
+		{3, nil, nil},
+		{4, nil, ErrNotFound{ID: 4}},
+	}
+	for _, tt := range tests {
+		aa, err := s.Accounts(context.Background(), tt.customerID)
+		if err != tt.err {
+			t.Errorf("customer %d: got error %v, want %v", tt.customerID, err, tt.err)
+		}
+		var got []int64
+		for _, a := range aa {
+			got = append(got, a.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("customer %d: got accounts %v, want %v", tt.customerID, got, tt.want)
+		}
+	}
+}
+
+func TestBalance(t *testing.T) {
+	as := stubAccounts{accounts: []*account.Account{testAccount(1, 1), testAccount(2, 1), testAccount(3, 2), testAccount(4, 2)}}
+	ps := stubPayments{balances: map[int64][]*payment.Balance{
+		1: {{AccountID: 1, Currency: "USD", Ledger: 1000, Available: 800}, {AccountID: 1, Currency: "EUR", Ledger: 50, Available: 50}},
+		2: {{AccountID: 2, Currency: "USD", Ledger: 250, Available: 250}},
+		3: {{AccountID: 3, Currency: "USD", Ledger: math.MaxInt64, Available: 0}},
+		4: {{AccountID: 4, Currency: "USD", Ledger: 1, Available: 0}},
+	}}
+	s := NewService(stubRepo{1: {ID: 1}, 2: {ID: 2}}, as, ps)
+
+	bb, err := s.Balance(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Balance{{Currency: "EUR", Ledger: 50, Available: 50}, {Currency: "USD", Ledger: 1250, Available: 1050}}
+	if !reflect.DeepEqual(bb, want) {
+		t.Errorf("got %+v, want %+v", bb, want)
+	}
+
+	if _, err := s.Balance(context.Background(), 2); err == nil {
+		t.Error("overflow: got no error")
+	} else if _, ok := err.(money.ErrOverflow); !ok {
+		t.Errorf("overflow: got %v, want money.ErrOverflow", err)
+	}
+}
@@ -0,0 +1,111 @@
+package customer
+
+import (
+	"coins/pkg/auth"
+	"coins/pkg/problem"
+	"coins/pkg/ratelimit"
+	"coins/pkg/response"
+	"coins/pkg/validation"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-kit/kit/log"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+)
+
+type errBadRequest struct {
+	Msg string
+}
+
+func (e errBadRequest) Error() string {
+	return e.Msg
+}
+
+// Code implements problem.Coder
+func (e errBadRequest) Code() string {
+	return problem.CodeInvalidParameter
+}
+
+// MakeHandler build handlers for customer transport of API v2, breaking changes land here while v1 stays frozen
+func MakeHandler(cs Service, authn auth.Authenticator, rl *ratelimit.Limiter, logger log.Logger) http.Handler {
+	const prefix = "/v2/customer/"
+	enc := response.NewEncoder(logger, false, 0)
+	opts := []kithttp.ServerOption{
+		kithttp.ServerBefore(kithttp.PopulateRequestContext, problem.PopulateRequestID, auth.HTTPToContext),
+		kithttp.ServerAfter(problem.SetRequestIDHeader),
+		kithttp.ServerErrorEncoder(enc.EncodeError),
+	}
+	read, write := rl.Wrap(auth.Require(authn, auth.ScopeAccountsRead)), rl.Wrap(auth.Require(authn, auth.ScopeAccountsWrite))
+
+	addCustomerHandler := kithttp.NewServer(
+		write(validation.Middleware(makeAddCustomerEndpoint(cs))),
+		decodeAddCustomerRequest,
+		enc.CreatedIn(prefix),
+		opts...,
+	)
+
+	getCustomerHandler := kithttp.NewServer(
+		read(validation.Middleware(makeGetCustomerEndpoint(cs))),
+		decodeGetCustomerRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	listAccountsHandler := kithttp.NewServer(
+		read(validation.Middleware(makeListAccountsEndpoint(cs))),
+		decodeGetCustomerRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	getBalanceHandler := kithttp.NewServer(
+		read(validation.Middleware(makeGetBalanceEndpoint(cs))),
+		decodeGetCustomerRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	r := mux.NewRouter()
+
+	r.Handle(prefix, addCustomerHandler).Methods("POST")
+	r.Handle(prefix+"{id}", getCustomerHandler).Methods("GET")
+	r.Handle(prefix+"{id}/accounts", listAccountsHandler).Methods("GET")
+	r.Handle(prefix+"{id}/balance", getBalanceHandler).Methods("GET")
+
+	return r
+}
+
+func decodeAddCustomerRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var body struct {
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+		Email     string `json:"email"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	return addCustomerRequest{
+		FirstName: body.FirstName,
+		LastName:  body.LastName,
+		Email:     body.Email,
+	}, nil
+}
+
+func decodeGetCustomerRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	idStr, ok := vars["id"]
+	if !ok {
+		return nil, errBadRequest{Msg: fmt.Sprintf("id param required")}
+	}
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return nil, errBadRequest{Msg: fmt.Sprintf("id param must be int")}
+	}
+	return getCustomerRequest{ID: id}, nil
+}
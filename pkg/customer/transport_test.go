@@ -0,0 +1,89 @@
+package customer
+
+import (
+	"coins/pkg/account"
+	"coins/pkg/auth"
+	"coins/pkg/ratelimit"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"golang.org/x/time/rate"
+)
+
+// stubService serve one customer with ID 1
+type stubService struct{}
+
+func (stubService) Get(_ context.Context, id int64) (*Customer, error) {
+	if id != 1 {
+		return nil, ErrNotFound{ID: id}
+	}
+	return &Customer{ID: 1, FirstName: "John", LastName: "Doe"}, nil
+}
+
+func (stubService) Store(_ context.Context, firstName, lastName, email string) (*Customer, error) {
+	return &Customer{ID: 1, FirstName: firstName, LastName: lastName, Email: email}, nil
+}
+
+func (stubService) Accounts(context.Context, int64) ([]*account.Account, error) {
+	return nil, nil
+}
+
+func (stubService) Balance(context.Context, int64) ([]*Balance, error) {
+	return nil, nil
+}
+
+type stubAuthn struct{}
+
+func (stubAuthn) Authenticate(context.Context, string) (*auth.Key, error) {
+	return &auth.Key{ID: 1, Scopes: []auth.Scope{auth.ScopeAccountsRead, auth.ScopeAccountsWrite}}, nil
+}
+
+func (stubAuthn) VerifyToken(string) (*auth.Claims, error) {
+	return nil, auth.ErrUnauthenticated{}
+}
+
+func TestTransportVersions(t *testing.T) {
+	rl := ratelimit.NewLimiter(rate.Inf, rate.Inf, 1, auth.Identity)
+	v1 := MakeHandlerV1(stubService{}, stubAuthn{}, rl, log.NewNopLogger())
+	v2 := MakeHandler(stubService{}, stubAuthn{}, rl, log.NewNopLogger())
+	tests := []struct {
+		name        string
+		handler     http.Handler
+		method      string
+		path        string
+		body        string
+		status      int
+		contentType string
+		location    string
+		contains    string
+	}{
+		{"v1 create", v1, "POST", "/customer/v1/", `{"first_name":"John","last_name":"Doe","email":"john@example.com"}`, http.StatusOK, "application/json; charset=utf-8", "", `"first_name":"John"`},
+		{"v1 get", v1, "GET", "/customer/v1/1", "", http.StatusOK, "application/json; charset=utf-8", "", `"id":1`},
+		{"v1 not found", v1, "GET", "/customer/v1/2", "", http.StatusNotFound, "application/json; charset=utf-8", "", `"error":"customer with ID 2 not found"`},
+		{"v2 create", v2, "POST", "/v2/customer/", `{"first_name":"John","last_name":"Doe","email":"john@example.com"}`, http.StatusCreated, "application/json; charset=utf-8", "/v2/customer/1", `"first_name":"John"`},
+		{"v2 get", v2, "GET", "/v2/customer/1", "", http.StatusOK, "application/json; charset=utf-8", "", `"id":1`},
+		{"v2 not found", v2, "GET", "/v2/customer/2", "", http.StatusNotFound, "application/problem+json", "", `"code":"CUSTOMER_NOT_FOUND"`},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		r.Header.Set(auth.APIKeyHeader, "token")
+		w := httptest.NewRecorder()
+		tt.handler.ServeHTTP(w, r)
+		if w.Code != tt.status {
+			t.Errorf("%s: got status %d, want %d", tt.name, w.Code, tt.status)
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, tt.contentType) {
+			t.Errorf("%s: got content type %q, want %q", tt.name, ct, tt.contentType)
+		}
+		if loc := w.Header().Get("Location"); loc != tt.location {
+			t.Errorf("%s: got location %q, want %q", tt.name, loc, tt.location)
+		}
+		if !strings.Contains(w.Body.String(), tt.contains) {
+			t.Errorf("%s: got body %s, want it to contain %s", tt.name, w.Body.String(), tt.contains)
+		}
+	}
+}
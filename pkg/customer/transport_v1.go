@@ -0,0 +1,84 @@
+package customer
+
+import (
+	"coins/pkg/auth"
+	"coins/pkg/problem"
+	"coins/pkg/ratelimit"
+	"coins/pkg/response"
+	"coins/pkg/validation"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-kit/kit/log"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+)
+
+// MakeHandlerV1 build handlers for customer transport of API v1. v1 is frozen: it always answers with legacy
+// statuses(200 for every success) and `{"error": "..."}` bodies and decodes requests with its own decoders
+func MakeHandlerV1(cs Service, authn auth.Authenticator, rl *ratelimit.Limiter, logger log.Logger) http.Handler {
+	const prefix = "/customer/v1/"
+	enc := response.NewEncoder(logger, true, http.StatusOK)
+	opts := []kithttp.ServerOption{
+		kithttp.ServerBefore(kithttp.PopulateRequestContext, problem.PopulateRequestID, auth.HTTPToContext),
+		kithttp.ServerAfter(problem.SetRequestIDHeader),
+		kithttp.ServerErrorEncoder(enc.EncodeError),
+	}
+	read, write := rl.Wrap(auth.Require(authn, auth.ScopeAccountsRead)), rl.Wrap(auth.Require(authn, auth.ScopeAccountsWrite))
+
+	addCustomerHandler := kithttp.NewServer(
+		write(validation.Middleware(makeAddCustomerEndpoint(cs))),
+		decodeAddCustomerRequestV1,
+		enc.CreatedIn(prefix),
+		opts...,
+	)
+
+	getCustomerHandler := kithttp.NewServer(
+		read(validation.Middleware(makeGetCustomerEndpoint(cs))),
+		decodeGetCustomerRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	listAccountsHandler := kithttp.NewServer(
+		read(validation.Middleware(makeListAccountsEndpoint(cs))),
+		decodeGetCustomerRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	getBalanceHandler := kithttp.NewServer(
+		read(validation.Middleware(makeGetBalanceEndpoint(cs))),
+		decodeGetCustomerRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	r := mux.NewRouter()
+
+	r.Handle(prefix, addCustomerHandler).Methods("POST")
+	r.Handle(prefix+"{id}", getCustomerHandler).Methods("GET")
+	r.Handle(prefix+"{id}/accounts", listAccountsHandler).Methods("GET")
+	r.Handle(prefix+"{id}/balance", getBalanceHandler).Methods("GET")
+
+	return r
+}
+
+func decodeAddCustomerRequestV1(_ context.Context, r *http.Request) (interface{}, error) {
+	var body struct {
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+		Email     string `json:"email"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	return addCustomerRequest{
+		FirstName: body.FirstName,
+		LastName:  body.LastName,
+		Email:     body.Email,
+	}, nil
+}
@@ -0,0 +1,17 @@
+package customer
+
+import "coins/pkg/validation"
+
+// column sizes
+const (
+	nameMaxLength  = 50
+	emailMaxLength = 254
+)
+
+func (r addCustomerRequest) Validate() error {
+	var vv validation.Violations
+	vv.Name("first_name", r.FirstName, nameMaxLength)
+	vv.Name("last_name", r.LastName, nameMaxLength)
+	vv.Email("email", r.Email, emailMaxLength)
+	return vv.Err()
+}
@@ -0,0 +1,33 @@
+package customer
+
+import (
+	"coins/pkg/validation"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		req  addCustomerRequest
+		want []string
+	}{
+		{"customer", addCustomerRequest{FirstName: "Alice", LastName: "Smith", Email: "alice@example.com"}, nil},
+		{"empty", addCustomerRequest{}, []string{"first_name:required", "last_name:required", "email:required"}},
+		{"bad email", addCustomerRequest{FirstName: "Alice", LastName: "Smith", Email: "alice"}, []string{"email:invalid"}},
+		{"long email", addCustomerRequest{FirstName: "Alice", LastName: "Smith", Email: strings.Repeat("a", emailMaxLength) + "@example.com"}, []string{"email:too_long"}},
+		{"long name", addCustomerRequest{FirstName: strings.Repeat("a", nameMaxLength+1), LastName: "Smith", Email: "alice@example.com"}, []string{"first_name:too_long"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		if err := tt.req.Validate(); err != nil {
+			for _, v := range err.(validation.Error).Violations {
+				got = append(got, v.Field+":"+v.Code)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
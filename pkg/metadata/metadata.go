@@ -0,0 +1,95 @@
+package metadata
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Metadata arbitrary client data attached to accounts and transactions, like order IDs or tags.
+// Stored as JSONB, so it can be searched by containment of key/value pairs
+type Metadata map[string]interface{}
+
+// Value implements driver.Valuer, nil metadata stored as empty object
+func (m Metadata) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(map[string]interface{}(m))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner for JSONB column
+func (m *Metadata) Scan(src interface{}) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("unable to scan %T into metadata.Metadata", src)
+	}
+	var v map[string]interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if len(v) == 0 {
+		v = nil
+	}
+	*m = v
+	return nil
+}
+
+// Parse decode metadata sent as JSON object text, like in gRPC requests, empty string is no metadata
+func Parse(s string) (Metadata, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var m Metadata
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// String return metadata as JSON object text, empty for no metadata
+func (m Metadata) String() string {
+	if len(m) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(map[string]interface{}(m))
+	return string(b)
+}
+
+// Filter key/value pairs metadata must contain, only string values can be matched
+type Filter map[string]string
+
+// JSON return filter as JSON object for containment(@>) query
+func (f Filter) JSON() string {
+	b, _ := json.Marshal(map[string]string(f))
+	return string(b)
+}
+
+// FilterFromQuery build Filter from query params like `metadata[order_id]=42`, nil when there are no such params
+func FilterFromQuery(q url.Values) Filter {
+	var f Filter
+	for k, vv := range q {
+		if !strings.HasPrefix(k, "metadata[") || !strings.HasSuffix(k, "]") || len(vv) == 0 {
+			continue
+		}
+		if f == nil {
+			f = make(Filter)
+		}
+		f[k[len("metadata["):len(k)-1]] = vv[0]
+	}
+	return f
+}
@@ -0,0 +1,83 @@
+package metadata
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestValueScan(t *testing.T) {
+	tests := []struct {
+		md   Metadata
+		want Metadata
+	}{
+		{nil, nil},
+		{Metadata{}, nil},
+		{Metadata{"order": "42", "paid": true, "items": 3.0}, Metadata{"order": "42", "paid": true, "items": 3.0}},
+	}
+	for _, tt := range tests {
+		v, err := tt.md.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Metadata
+		if err := got.Scan([]byte(v.(string))); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v: got %v, want %v", tt.md, got, tt.want)
+		}
+	}
+	var md Metadata
+	if err := md.Scan(nil); err != nil || md != nil {
+		t.Errorf("NULL: got %v, %v", md, err)
+	}
+	if err := md.Scan(42); err == nil {
+		t.Error("int: got no error")
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Metadata
+		err  bool
+	}{
+		{"", nil, false},
+		{`{"order":"42"}`, Metadata{"order": "42"}, false},
+		{`["order"]`, nil, true},
+		{`{`, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.s)
+		if (err != nil) != tt.err || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %v, %v, want %v", tt.s, got, err, tt.want)
+		}
+		if err == nil && got.String() != tt.s {
+			t.Errorf("%q: got string %q", tt.s, got.String())
+		}
+	}
+}
+
+func TestFilterFromQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  Filter
+	}{
+		{"", nil},
+		{"limit=10&metadata=x&metadata[=y", nil},
+		{"metadata[order]=42&metadata[tag]=vip&metadata[tag]=new&limit=10", Filter{"order": "42", "tag": "vip"}},
+	}
+	for _, tt := range tests {
+		q, err := url.ParseQuery(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := FilterFromQuery(q); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+	if got := (Filter{"order": "42"}).JSON(); got != `{"order":"42"}` {
+		t.Errorf("got JSON %s", got)
+	}
+}
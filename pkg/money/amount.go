@@ -0,0 +1,136 @@
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of fractional digits carried by an Amount
+const Scale = 2
+
+// Amount is an exact monetary value stored as an integer number of minor units (cents).
+//
+// Rounding rules: amounts are never rounded implicitly. Parse and JSON decoding reject
+// values with more than Scale fractional digits instead of rounding them, and all arithmetic
+// on Amount is integer arithmetic, so sums and differences are exact. The only operation
+// producing fractions of a minor unit is Convert, which rounds half to even.
+//
+// Amounts read from NUMERIC columns may be close to the int64 range, so sums of stored amounts
+// use Add and Sub, which raise ErrOverflow instead of wrapping around.
+type Amount int64
+
+// FromMinor build Amount from number of minor units
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// Minor return number of minor units
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// IsPositive return true when amount greater than zero
+func (a Amount) IsPositive() bool {
+	return a > 0
+}
+
+// IsNegative return true when amount less than zero
+func (a Amount) IsNegative() bool {
+	return a < 0
+}
+
+// Add return a+b, raise ErrOverflow when the sum doesn't fit Amount
+func (a Amount) Add(b Amount) (Amount, error) {
+	s := a + b
+	if (b > 0 && s < a) || (b < 0 && s > a) {
+		return 0, ErrOverflow{Op: a.String() + " + " + b.String()}
+	}
+	return s, nil
+}
+
+// Sub return a-b, raise ErrOverflow when the difference doesn't fit Amount
+func (a Amount) Sub(b Amount) (Amount, error) {
+	d := a - b
+	if (b > 0 && d > a) || (b < 0 && d < a) {
+		return 0, ErrOverflow{Op: a.String() + " - " + b.String()}
+	}
+	return d, nil
+}
+
+// Parse decimal string like "10", "-0.5" or "33.50" into Amount,
+// raise ErrInvalidAmount for malformed input and ErrTooPrecise when there are more than Scale fractional digits
+func Parse(s string) (Amount, error) {
+	v, err := parseFixed(s, Scale)
+	switch err {
+	case nil:
+		return Amount(v), nil
+	case errTooPrecise:
+		return 0, ErrTooPrecise{Value: s}
+	default:
+		return 0, ErrInvalidAmount{Value: s}
+	}
+}
+
+// String return amount as decimal string with exactly Scale fractional digits, e.g. "33.50"
+func (a Amount) String() string {
+	return formatFixed(int64(a), Scale)
+}
+
+// MarshalJSON encode amount as JSON number with exactly Scale fractional digits
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON decode amount from JSON number or string without going through float64
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		unquoted, err := strconv.Unquote(s)
+		if err != nil {
+			return ErrInvalidAmount{Value: s}
+		}
+		s = unquoted
+	}
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// Value implements driver.Valuer, amount stored as decimal string into NUMERIC column
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Scan implements sql.Scanner for NUMERIC column
+func (a *Amount) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*a = 0
+		return nil
+	case []byte:
+		return a.scanString(string(v))
+	case string:
+		return a.scanString(v)
+	case int64:
+		return a.scanString(strconv.FormatInt(v, 10))
+	default:
+		return fmt.Errorf("unable to scan %T into money.Amount", src)
+	}
+}
+
+func (a *Amount) scanString(s string) error {
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
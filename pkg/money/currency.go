@@ -0,0 +1,73 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Currency ISO 4217 currency code
+type Currency string
+
+// supported currencies
+const (
+	EUR Currency = "EUR"
+	USD Currency = "USD"
+	GBP Currency = "GBP"
+)
+
+var currencies = map[Currency]bool{
+	EUR: true,
+	USD: true,
+	GBP: true,
+}
+
+// ParseCurrency return Currency for code (case-insensitive) or ErrUnsupportedCurrency
+func ParseCurrency(code string) (Currency, error) {
+	c := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if !currencies[c] {
+		return "", ErrUnsupportedCurrency{Value: code}
+	}
+	return c, nil
+}
+
+func (c Currency) String() string {
+	return string(c)
+}
+
+// UnmarshalJSON decode currency code and validate it
+func (c *Currency) UnmarshalJSON(data []byte) error {
+	var code string
+	if err := json.Unmarshal(data, &code); err != nil {
+		return err
+	}
+	if code == "" {
+		*c = ""
+		return nil
+	}
+	v, err := ParseCurrency(code)
+	if err != nil {
+		return err
+	}
+	*c = v
+	return nil
+}
+
+// Value implements driver.Valuer
+func (c Currency) Value() (driver.Value, error) {
+	return string(c), nil
+}
+
+// Scan implements sql.Scanner
+func (c *Currency) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		*c = Currency(strings.TrimSpace(string(v)))
+	case string:
+		*c = Currency(strings.TrimSpace(v))
+	default:
+		return fmt.Errorf("unable to scan %T into money.Currency", src)
+	}
+	return nil
+}
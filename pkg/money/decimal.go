@@ -0,0 +1,93 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	errMalformed  = errors.New("malformed decimal")
+	errTooPrecise = errors.New("too many fractional digits")
+)
+
+// parseFixed parse decimal string into integer scaled by 10^scale without rounding
+func parseFixed(s string, scale int) (int64, error) {
+	str := strings.TrimSpace(s)
+	neg := false
+	switch {
+	case strings.HasPrefix(str, "-"):
+		neg = true
+		str = str[1:]
+	case strings.HasPrefix(str, "+"):
+		str = str[1:]
+	}
+
+	intPart, fracPart := str, ""
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		intPart, fracPart = str[:i], str[i+1:]
+	}
+	if intPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, errMalformed
+	}
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) > scale {
+		return 0, errTooPrecise
+	}
+	fracPart += strings.Repeat("0", scale-len(fracPart))
+
+	v, err := strconv.ParseInt(intPart+fracPart, 10, 64)
+	if err != nil {
+		return 0, errMalformed
+	}
+	if neg {
+		v = -v
+	}
+	return v, nil
+}
+
+// formatFixed format integer scaled by 10^scale as decimal string with exactly scale fractional digits
+func formatFixed(v int64, scale int) string {
+	sign := ""
+	if v < 0 {
+		sign = "-"
+	}
+	s := new(big.Int).Abs(big.NewInt(v)).String()
+	if len(s) <= scale {
+		s = strings.Repeat("0", scale-len(s)+1) + s
+	}
+	if scale == 0 {
+		return sign + s
+	}
+	return fmt.Sprintf("%s%s.%s", sign, s[:len(s)-scale], s[len(s)-scale:])
+}
+
+// divRoundHalfEven return x/y rounded half to even
+func divRoundHalfEven(x, y *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(x, y, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	twice := new(big.Int).Abs(r)
+	twice.Lsh(twice, 1)
+	cmp := twice.Cmp(new(big.Int).Abs(y))
+	if cmp > 0 || (cmp == 0 && q.Bit(0) == 1) {
+		if (x.Sign() < 0) != (y.Sign() < 0) {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+package money
+
+import (
+	"coins/pkg/problem"
+	"fmt"
+)
+
+// ErrInvalidAmount raised when value can't be parsed as decimal amount
+type ErrInvalidAmount struct {
+	Value string
+}
+
+func (e ErrInvalidAmount) Error() string {
+	return fmt.Sprintf("invalid amount %q", e.Value)
+}
+
+// Code implements problem.Coder
+func (e ErrInvalidAmount) Code() string {
+	return problem.CodeInvalidAmount
+}
+
+// ErrTooPrecise raised when amount has more fractional digits than supported
+type ErrTooPrecise struct {
+	Value string
+}
+
+func (e ErrTooPrecise) Error() string {
+	return fmt.Sprintf("amount %q has more than %d fractional digits", e.Value, Scale)
+}
+
+// Code implements problem.Coder
+func (e ErrTooPrecise) Code() string {
+	return problem.CodeAmountTooPrecise
+}
+
+// ErrOverflow raised when result of arithmetic on amounts, like sum or conversion, doesn't fit Amount
+type ErrOverflow struct {
+	Op string
+}
+
+func (e ErrOverflow) Error() string {
+	return fmt.Sprintf("amount %s is out of range", e.Op)
+}
+
+// Code implements problem.Coder
+func (e ErrOverflow) Code() string {
+	return problem.CodeAmountOverflow
+}
+
+// ErrUnsupportedCurrency raised when currency code is unknown
+type ErrUnsupportedCurrency struct {
+	Value string
+}
+
+func (e ErrUnsupportedCurrency) Error() string {
+	return fmt.Sprintf("unsupported currency %q", e.Value)
+}
+
+// Code implements problem.Coder
+func (e ErrUnsupportedCurrency) Code() string {
+	return problem.CodeUnsupportedCurrency
+}
+
+// ErrInvalidRate raised when exchange rate can't be parsed or isn't positive
+type ErrInvalidRate struct {
+	Value string
+}
+
+func (e ErrInvalidRate) Error() string {
+	return fmt.Sprintf("invalid exchange rate %q", e.Value)
+}
+
+// Code implements problem.Coder
+func (e ErrInvalidRate) Code() string {
+	return problem.CodeInvalidRate
+}
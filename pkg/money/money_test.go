@@ -0,0 +1,287 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestParseFixed(t *testing.T) {
+	tests := []struct {
+		in    string
+		scale int
+		want  int64
+		err   error
+	}{
+		{"10", 2, 1000, nil},
+		{"33.5", 2, 3350, nil},
+		{"33.50", 2, 3350, nil},
+		{"33.500", 2, 3350, nil},
+		{"0.01", 2, 1, nil},
+		{"-0.5", 2, -50, nil},
+		{"+1.25", 2, 125, nil},
+		{" 7 ", 2, 700, nil},
+		{"0.001", 2, 0, errTooPrecise},
+		{"-1.005", 2, 0, errTooPrecise},
+		{"1.08451234", 8, 108451234, nil},
+		{"1.084512345", 8, 0, errTooPrecise},
+		{"", 2, 0, errMalformed},
+		{"-", 2, 0, errMalformed},
+		{".5", 2, 0, errMalformed},
+		{"1.2.3", 2, 0, errMalformed},
+		{"1e3", 2, 0, errMalformed},
+		{"--1", 2, 0, errMalformed},
+		{"92233720368547758.07", 2, math.MaxInt64, nil},
+		{"92233720368547758.08", 2, 0, errMalformed},
+	}
+	for _, tt := range tests {
+		got, err := parseFixed(tt.in, tt.scale)
+		if err != tt.err || got != tt.want {
+			t.Errorf("parseFixed(%q, %d) = %d, %v; want %d, %v", tt.in, tt.scale, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+		err  error
+	}{
+		{"100", 10000, nil},
+		{"-0.5", -50, nil},
+		{"0.001", 0, ErrTooPrecise{Value: "0.001"}},
+		{"abc", 0, ErrInvalidAmount{Value: "abc"}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != tt.err || got != tt.want {
+			t.Errorf("Parse(%q) = %v, %v; want %v, %v", tt.in, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestAmountString(t *testing.T) {
+	tests := []struct {
+		in   Amount
+		want string
+	}{
+		{0, "0.00"},
+		{1, "0.01"},
+		{-1, "-0.01"},
+		{3350, "33.50"},
+		{-12345, "-123.45"},
+		{math.MinInt64, "-92233720368547758.08"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Amount(%d).String() = %q; want %q", int64(tt.in), got, tt.want)
+		}
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	var v struct {
+		Number Amount `json:"number"`
+		String Amount `json:"string"`
+	}
+	if err := json.Unmarshal([]byte(`{"number": 0.1, "string": "-2.30"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Number != 10 || v.String != -230 {
+		t.Fatalf("got %d and %d; want 10 and -230", v.Number, v.String)
+	}
+	if err := json.Unmarshal([]byte(`{"number": 0.105}`), &v); err == nil {
+		t.Fatal("too precise amount accepted")
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"number":0.10,"string":-2.30}` {
+		t.Fatalf("got %s", b)
+	}
+}
+
+func TestDivRoundHalfEven(t *testing.T) {
+	tests := []struct {
+		x, y, want int64
+	}{
+		{10, 4, 2},   // 2.5
+		{14, 4, 4},   // 3.5
+		{-10, 4, -2}, // -2.5
+		{-14, 4, -4}, // -3.5
+		{10, -4, -2},
+		{11, 4, 3}, // 2.75
+		{9, 4, 2},  // 2.25
+		{-9, 4, -2},
+		{8, 4, 2},
+	}
+	for _, tt := range tests {
+		got := divRoundHalfEven(big.NewInt(tt.x), big.NewInt(tt.y)).Int64()
+		if got != tt.want {
+			t.Errorf("divRoundHalfEven(%d, %d) = %d; want %d", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		rate   string
+		want   Amount
+	}{
+		{10000, "1.0845", 10845},
+		{1, "0.5", 0},   // 0.005 rounds to even 0.00
+		{3, "0.5", 2},   // 0.015 rounds to even 0.02
+		{-3, "0.5", -2}, // -0.015 rounds to even -0.02
+		{12345, "1", 12345},
+	}
+	for _, tt := range tests {
+		r, err := ParseRate(tt.rate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := tt.amount.Convert(r)
+		if err != nil || got != tt.want {
+			t.Errorf("%v.Convert(%s) = %v, %v; want %v", tt.amount, tt.rate, got, err, tt.want)
+		}
+	}
+}
+
+func TestConvertOverflow(t *testing.T) {
+	r, err := ParseRate("2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Amount(math.MaxInt64).Convert(r); err == nil {
+		t.Fatal("overflow isn't reported")
+	}
+	if _, err := Amount(math.MinInt64).Convert(r); err == nil {
+		t.Fatal("negative overflow isn't reported")
+	}
+}
+
+func TestInverse(t *testing.T) {
+	tests := []struct {
+		rate string
+		want string
+	}{
+		{"2", "0.5"},
+		{"1", "1"},
+		{"3", "0.33333333"},
+		{"1.0845", "0.92208391"},
+		{"0.00000001", "100000000"},
+	}
+	for _, tt := range tests {
+		r, err := ParseRate(tt.rate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := r.Inverse()
+		if err != nil || got.String() != tt.want {
+			t.Errorf("%s.Inverse() = %v, %v; want %s", tt.rate, got, err, tt.want)
+		}
+	}
+	if _, err := Rate(math.MaxInt64).Inverse(); err == nil {
+		t.Fatal("inverse rounded to zero is accepted")
+	}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	for _, s := range []string{"1.0845", "0.8571", "1.1667", "3"} {
+		r, err := ParseRate(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inv, err := r.Inverse()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, a := range []Amount{1, 99, 10000, 123456789} {
+			converted, err := a.Convert(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			back, err := converted.Convert(inv)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// each conversion rounds by at most half a minor unit, scaled by the rate of the way back
+			if d := back - a; d < -2 || d > 2 {
+				t.Errorf("%v converted at %s and back is %v", a, s, back)
+			}
+		}
+	}
+}
+
+func TestAddSub(t *testing.T) {
+	tests := []struct {
+		a, b     Amount
+		sum, dif Amount
+		sumErr   bool
+		difErr   bool
+	}{
+		{100, 50, 150, 50, false, false},
+		{-100, 50, -50, -150, false, false},
+		{math.MaxInt64, 1, 0, math.MaxInt64 - 1, true, false},
+		{math.MaxInt64, -1, math.MaxInt64 - 1, 0, false, true},
+		{math.MinInt64, -1, 0, math.MinInt64 + 1, true, false},
+		{math.MinInt64, 1, math.MinInt64 + 1, 0, false, true},
+		{0, math.MinInt64, math.MinInt64, 0, false, true},
+		{-1, math.MinInt64, 0, math.MaxInt64, true, false},
+	}
+	for _, tt := range tests {
+		sum, err := tt.a.Add(tt.b)
+		if (err != nil) != tt.sumErr || (err == nil && sum != tt.sum) {
+			t.Errorf("%d + %d = %d, %v", int64(tt.a), int64(tt.b), int64(sum), err)
+		}
+		if _, ok := err.(ErrOverflow); err != nil && !ok {
+			t.Errorf("%d + %d raised %T", int64(tt.a), int64(tt.b), err)
+		}
+		dif, err := tt.a.Sub(tt.b)
+		if (err != nil) != tt.difErr || (err == nil && dif != tt.dif) {
+			t.Errorf("%d - %d = %d, %v", int64(tt.a), int64(tt.b), int64(dif), err)
+		}
+	}
+}
+
+func TestParseCurrency(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Currency
+		err  error
+	}{
+		{"EUR", EUR, nil},
+		{"usd", USD, nil},
+		{" gbp ", GBP, nil},
+		{"JPY", "", ErrUnsupportedCurrency{Value: "JPY"}},
+		{"", "", ErrUnsupportedCurrency{Value: ""}},
+	}
+	for _, tt := range tests {
+		got, err := ParseCurrency(tt.in)
+		if err != tt.err || got != tt.want {
+			t.Errorf("ParseCurrency(%q) = %v, %v; want %v, %v", tt.in, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestCurrencyJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Currency
+		err  error
+	}{
+		{`"eur"`, EUR, nil},
+		{`""`, "", nil},
+		{`"XXX"`, "", ErrUnsupportedCurrency{Value: "XXX"}},
+	}
+	for _, tt := range tests {
+		var got Currency
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if err != tt.err || got != tt.want {
+			t.Errorf("Unmarshal(%s) = %v, %v; want %v, %v", tt.in, got, err, tt.want, tt.err)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RateScale is the number of fractional digits carried by a Rate
+const RateScale = 8
+
+// Rate is an exchange rate, amount of target currency for one unit of source currency,
+// stored as an integer scaled by 10^RateScale
+type Rate int64
+
+// OneRate rate used for same currency transfers
+const OneRate Rate = 100000000
+
+// ParseRate parse decimal string like "1.0845" into Rate, raise ErrInvalidRate for malformed,
+// non-positive or too precise input
+func ParseRate(s string) (Rate, error) {
+	v, err := parseFixed(s, RateScale)
+	if err != nil || v <= 0 {
+		return 0, ErrInvalidRate{Value: s}
+	}
+	return Rate(v), nil
+}
+
+// Inverse return rate for the opposite direction, rounded half to even to RateScale digits,
+// raise ErrInvalidRate when the inverse rounds to zero
+func (r Rate) Inverse() (Rate, error) {
+	if r <= 0 {
+		return 0, ErrInvalidRate{Value: r.String()}
+	}
+	one := new(big.Int).Mul(big.NewInt(int64(OneRate)), big.NewInt(int64(OneRate)))
+	v := divRoundHalfEven(one, big.NewInt(int64(r)))
+	if !v.IsInt64() || v.Sign() <= 0 {
+		return 0, ErrInvalidRate{Value: r.String()}
+	}
+	return Rate(v.Int64()), nil
+}
+
+func (r Rate) String() string {
+	return strings.TrimRight(strings.TrimRight(formatFixed(int64(r), RateScale), "0"), ".")
+}
+
+// Convert amount into target currency using rate, result rounded half to even to the nearest minor unit,
+// raise ErrOverflow when the result doesn't fit Amount
+func (a Amount) Convert(r Rate) (Amount, error) {
+	x := new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(int64(r)))
+	v := divRoundHalfEven(x, big.NewInt(int64(OneRate)))
+	if !v.IsInt64() {
+		return 0, ErrOverflow{Op: a.String() + " converted at rate " + r.String()}
+	}
+	return Amount(v.Int64()), nil
+}
+
+// MarshalJSON encode rate as JSON number
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON decode rate from JSON number or string without going through float64
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		unquoted, err := strconv.Unquote(s)
+		if err != nil {
+			return ErrInvalidRate{Value: s}
+		}
+		s = unquoted
+	}
+	v, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+// Value implements driver.Valuer, rate stored as decimal string into NUMERIC column
+func (r Rate) Value() (driver.Value, error) {
+	return formatFixed(int64(r), RateScale), nil
+}
+
+// Scan implements sql.Scanner for NUMERIC column
+func (r *Rate) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("unable to scan %T into money.Rate", src)
+	}
+	v, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
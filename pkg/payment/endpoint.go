@@ -2,6 +2,8 @@ package payment
 
 import (
 	"coins/pkg/account"
+	"coins/pkg/auth"
+	"coins/pkg/money"
 	"context"
 
 	"github.com/go-kit/kit/endpoint"
@@ -10,13 +12,16 @@ import (
 func makeGetBalanceEndpoint(s Service, as account.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(getBalanceRequest)
+		if err := auth.CheckOwner(ctx, req.ID); err != nil {
+			return getBalanceResponse{Err: err}, err
+		}
 
 		a, err := as.Get(ctx, req.ID)
 		if err != nil {
 			return getBalanceResponse{Err: err}, err
 		}
-		balance, err := s.GetBalance(ctx, a)
-		return getBalanceResponse{Balance: balance, Err: err}, err
+		balances, err := s.GetBalance(ctx, a)
+		return getBalanceResponse{Balances: balances, Err: err}, err
 	}
 }
 
@@ -25,37 +30,80 @@ type getBalanceRequest struct {
 }
 
 type getBalanceResponse struct {
-	Balance *Balance `json:"balance,omitempty"`
-	Err     error    `json:"err,omitempty"`
+	Balances []*Balance `json:"balances,omitempty"`
+	Err      error      `json:"-"`
+}
+
+func (r getBalanceResponse) Failed() error { return r.Err }
+
+func makeVerifyBalanceEndpoint(s Service, as account.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getBalanceRequest)
+		if err := auth.CheckOwner(ctx, req.ID); err != nil {
+			return verifyBalanceResponse{Err: err}, err
+		}
+
+		a, err := as.Get(ctx, req.ID)
+		if err != nil {
+			return verifyBalanceResponse{Err: err}, err
+		}
+		checks, err := s.VerifyBalance(ctx, a)
+		return verifyBalanceResponse{Checks: checks, Err: err}, err
+	}
+}
+
+type verifyBalanceResponse struct {
+	Checks []*BalanceCheck `json:"checks,omitempty"`
+	Err    error           `json:"-"`
 }
 
-func (r getBalanceResponse) error() error { return r.Err }
+func (r verifyBalanceResponse) Failed() error { return r.Err }
 
 func makeListTransactionsEndpoint(s Service, as account.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(listTransactionsRequest)
+		if err := auth.CheckOwner(ctx, req.ID); err != nil {
+			return listTransactionsResponse{Err: err}, err
+		}
 		a, err := as.Get(ctx, req.ID)
 		if err != nil {
 			return listTransactionsResponse{Err: err}, err
 		}
 
-		tt, err := s.ListTransactions(ctx, a)
-		return listTransactionsResponse{Transactions: tt, Err: err}, err
+		page, err := s.ListTransactions(ctx, a, req.Filter)
+		if err != nil {
+			return listTransactionsResponse{Err: err}, err
+		}
+		return listTransactionsResponse{
+			Transactions: page.Transactions,
+			NextCursor:   encodeCursor(page.Next),
+		}, nil
 	}
 }
 
 type listTransactionsRequest struct {
-	ID int64
+	ID     int64
+	Filter TransactionFilter
 }
 
 type listTransactionsResponse struct {
 	Transactions []*Transaction `json:"transactions,omitempty"`
-	Err          error          `json:"err,omitempty"`
+	NextCursor   string         `json:"next_cursor,omitempty"`
+	Err          error          `json:"-"`
 }
 
+func (r listTransactionsResponse) Failed() error { return r.Err }
+
 func makeTransferEndpoint(s Service, as account.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(transferRequest)
+		if err := auth.CheckOwner(ctx, req.From); err != nil {
+			return transferResponse{Err: err}, err
+		}
+		ctx, err := bindIdempotencyKey(ctx, req.IdempotencyKey, OperationTransfer, req)
+		if err != nil {
+			return transferResponse{Err: err}, err
+		}
 		from, err := as.Get(ctx, req.From)
 		if err != nil {
 			return transferResponse{Err: err}, err
@@ -65,41 +113,231 @@ func makeTransferEndpoint(s Service, as account.Service) endpoint.Endpoint {
 			return transferResponse{Err: err}, err
 		}
 
-		t, err := s.Transfer(ctx, from, to, req.Amount)
+		if req.Convert || req.QuoteID != 0 {
+			t, err := s.TransferFX(ctx, from, to, req.Amount, req.Currency, req.ToCurrency, req.QuoteID, req.Reference)
+			return transferResponse{Transaction: t, Err: err}, err
+		}
+		if req.ToCurrency != "" && req.ToCurrency != req.Currency {
+			err := ErrCurrencyMismatch{From: req.Currency, To: req.ToCurrency}
+			return transferResponse{Err: err}, err
+		}
+
+		t, err := s.Transfer(ctx, from, to, req.Amount, req.Currency, req.Reference)
 		return transferResponse{Transaction: t, Err: err}, err
 	}
 }
 
 type transferRequest struct {
-	From   int64
-	To     int64
-	Amount float64
+	From       int64
+	To         int64
+	Amount     money.Amount
+	Currency   money.Currency
+	ToCurrency money.Currency
+	Convert    bool
+	QuoteID    int64
+	Reference  Reference
+
+	IdempotencyKey string `json:"-"`
 }
 
 type transferResponse struct {
 	Transaction *Transaction `json:"transaction,omitempty"`
-	Err         error        `json:"err,omitempty"`
+	Err         error        `json:"-"`
+}
+
+func (r transferResponse) Failed() error { return r.Err }
+
+func makeQuoteEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(quoteRequest)
+		q, err := s.Quote(ctx, req.Amount, req.Currency, req.ToCurrency)
+		return quoteResponse{Quote: q, Err: err}, err
+	}
 }
 
+type quoteRequest struct {
+	Amount     money.Amount
+	Currency   money.Currency
+	ToCurrency money.Currency
+}
+
+type quoteResponse struct {
+	Quote *Quote `json:"quote,omitempty"`
+	Err   error  `json:"-"`
+}
+
+func (r quoteResponse) Failed() error { return r.Err }
+
 func makeTopUpEndpoint(s Service, as account.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(topUpRequest)
+		ctx, err := bindIdempotencyKey(ctx, req.IdempotencyKey, OperationTopUp, req)
+		if err != nil {
+			return topUpResponse{Err: err}, err
+		}
 		a, err := as.Get(ctx, req.AccountID)
 		if err != nil {
 			return topUpResponse{Err: err}, err
 		}
 
-		b, err := s.TopUp(ctx, a, req.Amount)
+		b, err := s.TopUp(ctx, a, req.Amount, req.Currency, req.Reference)
 		return topUpResponse{Balance: b, Err: err}, err
 	}
 }
 
 type topUpRequest struct {
 	AccountID int64
-	Amount    float64
+	Amount    money.Amount
+	Currency  money.Currency
+	Reference Reference
+
+	IdempotencyKey string `json:"-"`
 }
 
 type topUpResponse struct {
 	Balance *Balance `json:"balance,omitempty"`
-	Err     error    `json:"err,omitempty"`
+	Err     error    `json:"-"`
 }
+
+func (r topUpResponse) Failed() error { return r.Err }
+
+func makeWithdrawEndpoint(s Service, as account.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(withdrawRequest)
+		ctx, err := bindIdempotencyKey(ctx, req.IdempotencyKey, OperationWithdraw, req)
+		if err != nil {
+			return withdrawResponse{Err: err}, err
+		}
+		a, err := as.Get(ctx, req.AccountID)
+		if err != nil {
+			return withdrawResponse{Err: err}, err
+		}
+
+		t, err := s.Withdraw(ctx, a, req.Amount, req.Currency)
+		return withdrawResponse{Transaction: t, Err: err}, err
+	}
+}
+
+type withdrawRequest struct {
+	AccountID int64
+	Amount    money.Amount
+	Currency  money.Currency
+
+	IdempotencyKey string `json:"-"`
+}
+
+type withdrawResponse struct {
+	Transaction *Transaction `json:"transaction,omitempty"`
+	Err         error        `json:"-"`
+}
+
+func (r withdrawResponse) Failed() error { return r.Err }
+
+func makeConfirmWithdrawalEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(confirmWithdrawalRequest)
+		t, err := s.ConfirmWithdrawal(ctx, req.ID, req.Status)
+		return confirmWithdrawalResponse{Transaction: t, Err: err}, err
+	}
+}
+
+type confirmWithdrawalRequest struct {
+	ID     int64
+	Status Status
+}
+
+type confirmWithdrawalResponse struct {
+	Transaction *Transaction `json:"transaction,omitempty"`
+	Err         error        `json:"-"`
+}
+
+func (r confirmWithdrawalResponse) Failed() error { return r.Err }
+
+func makeAuthorizeEndpoint(s Service, as account.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(authorizeRequest)
+		from, err := as.Get(ctx, req.From)
+		if err != nil {
+			return holdResponse{Err: err}, err
+		}
+		to, err := as.Get(ctx, req.To)
+		if err != nil {
+			return holdResponse{Err: err}, err
+		}
+
+		h, err := s.Authorize(ctx, from, to, req.Amount, req.Currency)
+		return holdResponse{Hold: h, Err: err}, err
+	}
+}
+
+type authorizeRequest struct {
+	From     int64
+	To       int64
+	Amount   money.Amount
+	Currency money.Currency
+}
+
+type holdResponse struct {
+	Hold *Hold `json:"hold,omitempty"`
+	Err  error `json:"-"`
+}
+
+func (r holdResponse) Failed() error { return r.Err }
+
+func makeCaptureEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(captureRequest)
+		t, err := s.Capture(ctx, req.HoldID, req.Amount)
+		return captureResponse{Transaction: t, Err: err}, err
+	}
+}
+
+type captureRequest struct {
+	HoldID int64
+	Amount money.Amount
+}
+
+type captureResponse struct {
+	Transaction *Transaction `json:"transaction,omitempty"`
+	Err         error        `json:"-"`
+}
+
+func (r captureResponse) Failed() error { return r.Err }
+
+func makeVoidEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(voidRequest)
+		h, err := s.Void(ctx, req.HoldID)
+		return holdResponse{Hold: h, Err: err}, err
+	}
+}
+
+type voidRequest struct {
+	HoldID int64
+}
+
+func makeRefundEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(refundRequest)
+		ctx, err := bindIdempotencyKey(ctx, req.IdempotencyKey, OperationRefund, req)
+		if err != nil {
+			return refundResponse{Err: err}, err
+		}
+		t, err := s.Refund(ctx, req.TransactionID, req.Amount)
+		return refundResponse{Transaction: t, Err: err}, err
+	}
+}
+
+type refundRequest struct {
+	TransactionID int64
+	Amount        money.Amount
+
+	IdempotencyKey string `json:"-"`
+}
+
+type refundResponse struct {
+	Transaction *Transaction `json:"transaction,omitempty"`
+	Err         error        `json:"-"`
+}
+
+func (r refundResponse) Failed() error { return r.Err }
@@ -1,6 +1,10 @@
 package payment
 
-import "fmt"
+import (
+	"coins/pkg/money"
+	"coins/pkg/problem"
+	"fmt"
+)
 
 // ErrInsufficientFunds raised when account doesn't have sufficient funds
 type ErrInsufficientFunds struct {
@@ -10,3 +14,260 @@ type ErrInsufficientFunds struct {
 func (e ErrInsufficientFunds) Error() string {
 	return fmt.Sprintf("insufficient funds, account with ID %d", e.ID)
 }
+
+// Code implements problem.Coder
+func (e ErrInsufficientFunds) Code() string {
+	return problem.CodeInsufficientFunds
+}
+
+// ErrCurrencyMismatch raised when transfer legs are in different currencies and conversion wasn't requested
+type ErrCurrencyMismatch struct {
+	From money.Currency
+	To   money.Currency
+}
+
+func (e ErrCurrencyMismatch) Error() string {
+	return fmt.Sprintf("currency mismatch, unable to transfer %s to %s without conversion", e.From, e.To)
+}
+
+// Code implements problem.Coder
+func (e ErrCurrencyMismatch) Code() string {
+	return problem.CodeCurrencyMismatch
+}
+
+// ErrRateUnavailable raised when FXRateProvider has no rate for currency pair
+type ErrRateUnavailable struct {
+	From money.Currency
+	To   money.Currency
+}
+
+func (e ErrRateUnavailable) Error() string {
+	return fmt.Sprintf("exchange rate %s/%s unavailable", e.From, e.To)
+}
+
+// Code implements problem.Coder
+func (e ErrRateUnavailable) Code() string {
+	return problem.CodeRateUnavailable
+}
+
+// ErrQuoteNotFound raised when quote not found, was already used or belongs to another client
+type ErrQuoteNotFound struct {
+	ID int64
+}
+
+func (e ErrQuoteNotFound) Error() string {
+	return fmt.Sprintf("quote with ID %d not found", e.ID)
+}
+
+// Code implements problem.Coder
+func (e ErrQuoteNotFound) Code() string {
+	return problem.CodeQuoteNotFound
+}
+
+// ErrQuoteExpired raised when quote used after its TTL
+type ErrQuoteExpired struct {
+	ID int64
+}
+
+func (e ErrQuoteExpired) Error() string {
+	return fmt.Sprintf("quote with ID %d expired", e.ID)
+}
+
+// Code implements problem.Coder
+func (e ErrQuoteExpired) Code() string {
+	return problem.CodeQuoteExpired
+}
+
+// ErrQuoteMismatch raised when transfer doesn't match currencies or amount of the quote
+type ErrQuoteMismatch struct {
+	ID int64
+}
+
+func (e ErrQuoteMismatch) Error() string {
+	return fmt.Sprintf("transfer doesn't match quote with ID %d", e.ID)
+}
+
+// Code implements problem.Coder
+func (e ErrQuoteMismatch) Code() string {
+	return problem.CodeQuoteMismatch
+}
+
+// ErrIdempotencyConflict raised when idempotency key reused with a different request
+type ErrIdempotencyConflict struct {
+	Key string
+}
+
+func (e ErrIdempotencyConflict) Error() string {
+	return fmt.Sprintf("idempotency key %q already used for a different request", e.Key)
+}
+
+// Code implements problem.Coder
+func (e ErrIdempotencyConflict) Code() string {
+	return problem.CodeIdempotencyConflict
+}
+
+// ErrAccountBusy raised when account balance is locked by concurrent operations longer than the request allows
+type ErrAccountBusy struct {
+	ID int64
+}
+
+func (e ErrAccountBusy) Error() string {
+	return fmt.Sprintf("account with ID %d is busy, try again later", e.ID)
+}
+
+// Code implements problem.Coder
+func (e ErrAccountBusy) Code() string {
+	return problem.CodeAccountBusy
+}
+
+// ErrTransactionNotFound raised when transaction not found
+type ErrTransactionNotFound struct {
+	ID int64
+}
+
+func (e ErrTransactionNotFound) Error() string {
+	return fmt.Sprintf("transaction with ID %d not found", e.ID)
+}
+
+// Code implements problem.Coder
+func (e ErrTransactionNotFound) Code() string {
+	return problem.CodeTransactionNotFound
+}
+
+// ErrNotPending raised when confirming withdrawal which isn't pending anymore
+type ErrNotPending struct {
+	ID     int64
+	Status Status
+}
+
+func (e ErrNotPending) Error() string {
+	return fmt.Sprintf("withdrawal with ID %d is %s, not pending", e.ID, e.Status)
+}
+
+// Code implements problem.Coder
+func (e ErrNotPending) Code() string {
+	return problem.CodeWithdrawalNotPending
+}
+
+// ErrHoldNotFound raised when hold not found
+type ErrHoldNotFound struct {
+	ID int64
+}
+
+func (e ErrHoldNotFound) Error() string {
+	return fmt.Sprintf("hold with ID %d not found", e.ID)
+}
+
+// Code implements problem.Coder
+func (e ErrHoldNotFound) Code() string {
+	return problem.CodeHoldNotFound
+}
+
+// ErrHoldNotActive raised when capturing or voiding hold which was already captured or voided
+type ErrHoldNotActive struct {
+	ID     int64
+	Status HoldStatus
+}
+
+func (e ErrHoldNotActive) Error() string {
+	return fmt.Sprintf("hold with ID %d is %s", e.ID, e.Status)
+}
+
+// Code implements problem.Coder
+func (e ErrHoldNotActive) Code() string {
+	return problem.CodeHoldNotActive
+}
+
+// ErrHoldExpired raised when capturing hold after its TTL
+type ErrHoldExpired struct {
+	ID int64
+}
+
+func (e ErrHoldExpired) Error() string {
+	return fmt.Sprintf("hold with ID %d expired", e.ID)
+}
+
+// Code implements problem.Coder
+func (e ErrHoldExpired) Code() string {
+	return problem.CodeHoldExpired
+}
+
+// ErrCaptureExceedsHold raised when capture amount is greater than amount of the hold
+type ErrCaptureExceedsHold struct {
+	ID     int64
+	Amount money.Amount
+}
+
+func (e ErrCaptureExceedsHold) Error() string {
+	return fmt.Sprintf("capture exceeds amount %s of hold with ID %d", e.Amount, e.ID)
+}
+
+// Code implements problem.Coder
+func (e ErrCaptureExceedsHold) Code() string {
+	return problem.CodeCaptureExceedsHold
+}
+
+// ErrNotRefundable raised when refunding transaction which isn't a same currency transfer or capture
+type ErrNotRefundable struct {
+	ID int64
+}
+
+func (e ErrNotRefundable) Error() string {
+	return fmt.Sprintf("transaction with ID %d can't be refunded", e.ID)
+}
+
+// Code implements problem.Coder
+func (e ErrNotRefundable) Code() string {
+	return problem.CodeNotRefundable
+}
+
+// ErrRefundExceedsOriginal raised when refunds would exceed amount of the original transaction
+type ErrRefundExceedsOriginal struct {
+	ID        int64
+	Remaining money.Amount
+}
+
+func (e ErrRefundExceedsOriginal) Error() string {
+	return fmt.Sprintf("refund exceeds remaining amount %s of transaction with ID %d", e.Remaining, e.ID)
+}
+
+// Code implements problem.Coder
+func (e ErrRefundExceedsOriginal) Code() string {
+	return problem.CodeRefundExceedsOriginal
+}
+
+// ErrDuplicateExternalID raised when transaction with the same external ID already exists
+type ErrDuplicateExternalID struct {
+	ExternalID string
+}
+
+func (e ErrDuplicateExternalID) Error() string {
+	return fmt.Sprintf("transaction with external ID %q already exists", e.ExternalID)
+}
+
+// Code implements problem.Coder
+func (e ErrDuplicateExternalID) Code() string {
+	return problem.CodeDuplicateExternalID
+}
+
+// ErrLimitExceeded raised when operation breaks velocity limit of account tier or spending limit of account wallet,
+// Max is the limit that was hit
+type ErrLimitExceeded struct {
+	ID    int64
+	Limit Limit
+	Max   string
+}
+
+func (e ErrLimitExceeded) Error() string {
+	return fmt.Sprintf("%s limit %s exceeded, account with ID %d", e.Limit, e.Max, e.ID)
+}
+
+// Code implements problem.Coder
+func (e ErrLimitExceeded) Code() string {
+	return problem.CodeLimitExceeded
+}
+
+// Extensions implements problem.Extender
+func (e ErrLimitExceeded) Extensions() map[string]interface{} {
+	return map[string]interface{}{"limit": e.Limit, "max": e.Max}
+}
@@ -0,0 +1,58 @@
+package payment
+
+import (
+	"coins/pkg/metadata"
+	"coins/pkg/money"
+	"time"
+)
+
+// page size limits of ListTransactions
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
+
+// Direction of transaction relative to the listed account
+type Direction string
+
+// transaction directions, empty Direction matches both
+const (
+	DirectionIncoming Direction = "incoming"
+	DirectionOutgoing Direction = "outgoing"
+)
+
+// Order of listed transactions by date
+type Order string
+
+// transaction orders
+const (
+	OrderAsc  Order = "asc"
+	OrderDesc Order = "desc"
+)
+
+// Cursor position in the list of transactions, points to the last transaction of the previous page
+type Cursor struct {
+	Date time.Time
+	ID   int64
+}
+
+// TransactionFilter filters and page of ListTransactions, zero values don't filter
+type TransactionFilter struct {
+	After        *Cursor
+	Limit        int
+	Order        Order
+	Since        time.Time
+	Until        time.Time
+	Direction    Direction
+	Counterparty int64
+	MinAmount    *money.Amount
+	MaxAmount    *money.Amount
+	ExternalID   string
+	Metadata     metadata.Filter
+}
+
+// TransactionPage page of transactions, Next is nil on the last page
+type TransactionPage struct {
+	Transactions []*Transaction
+	Next         *Cursor
+}
@@ -0,0 +1,67 @@
+package payment
+
+import (
+	"coins/pkg/auth"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// operations protected by idempotency keys
+const (
+	OperationTransfer = "transfer"
+	OperationTopUp    = "topup"
+	OperationWithdraw = "withdraw"
+	OperationRefund   = "refund"
+)
+
+// IdempotencyKey client provided key of a money movement request, repeated request with the same key and
+// the same RequestHash returns the original result, with different RequestHash raise ErrIdempotencyConflict.
+// Keys are scoped by Owner, identity of the authenticated client, so clients picking the same key don't collide
+type IdempotencyKey struct {
+	Key         string
+	Operation   string
+	Owner       string
+	RequestHash string
+}
+
+// maxIdempotencyKeyLength longest key accepted from client
+const maxIdempotencyKeyLength = 255
+
+// bindIdempotencyKey return context carrying optional key of authenticated client bound to hash of the decoded
+// endpoint request, so the same request has the same hash whichever transport it came from
+func bindIdempotencyKey(ctx context.Context, key, operation string, request interface{}) (context.Context, error) {
+	if key == "" {
+		return ctx, nil
+	}
+	hash, err := hashRequest(request)
+	if err != nil {
+		return ctx, err
+	}
+	owner, _ := auth.Identity(ctx)
+	return WithIdempotencyKey(ctx, IdempotencyKey{Key: key, Operation: operation, Owner: owner, RequestHash: hash}), nil
+}
+
+type idempotencyKeyCtx struct{}
+
+// WithIdempotencyKey return context carrying idempotency key for the repository
+func WithIdempotencyKey(ctx context.Context, k IdempotencyKey) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx{}, k)
+}
+
+// IdempotencyKeyFromContext return idempotency key of the request if client provided one
+func IdempotencyKeyFromContext(ctx context.Context) (IdempotencyKey, bool) {
+	k, ok := ctx.Value(idempotencyKeyCtx{}).(IdempotencyKey)
+	return k, ok && k.Key != ""
+}
+
+// hashRequest return hash of decoded request, so formatting of the body doesn't matter
+func hashRequest(body interface{}) (string, error) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,65 @@
+package payment
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestBindIdempotencyKey(t *testing.T) {
+	ctx := asKey(t, 1)
+	req := topUpRequest{AccountID: 1, Amount: 1000, Currency: "EUR"}
+
+	if ctx, err := bindIdempotencyKey(ctx, "", OperationTopUp, req); err != nil {
+		t.Fatal(err)
+	} else if _, ok := IdempotencyKeyFromContext(ctx); ok {
+		t.Error("request without key got one")
+	}
+
+	bound := func(ctx context.Context, req interface{}) IdempotencyKey {
+		t.Helper()
+		ctx, err := bindIdempotencyKey(ctx, "k1", OperationTopUp, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k, ok := IdempotencyKeyFromContext(ctx)
+		if !ok {
+			t.Fatal("key isn't bound")
+		}
+		return k
+	}
+	k := bound(ctx, req)
+	if k.Key != "k1" || k.Operation != OperationTopUp || k.Owner != "key:1" || k.RequestHash == "" {
+		t.Errorf("got %+v", k)
+	}
+	if other := bound(ctx, req); other != k {
+		t.Errorf("the same request: got %+v, want %+v", other, k)
+	}
+	req.Amount = 2000
+	if other := bound(ctx, req); other.RequestHash == k.RequestHash {
+		t.Error("other request has the same hash")
+	}
+	if other := bound(asKey(t, 2), topUpRequest{AccountID: 1, Amount: 1000, Currency: "EUR"}); other.Owner != "key:2" {
+		t.Errorf("key of other client: got owner %q", other.Owner)
+	}
+}
+
+func TestDecodeIdempotencyKey(t *testing.T) {
+	tests := []struct {
+		key string
+		err bool
+	}{
+		{"", false},
+		{"5d0c2a4e-8f7a-4c55-9d0b-3f0e2b1c9a77", false},
+		{strings.Repeat("k", maxIdempotencyKeyLength), false},
+		{strings.Repeat("k", maxIdempotencyKeyLength+1), true},
+	}
+	for _, tt := range tests {
+		r := newRequest("POST", "/v2/payment/transfer", "")
+		r.Header.Set("Idempotency-Key", tt.key)
+		got, err := decodeIdempotencyKey(r)
+		if (err != nil) != tt.err || (err == nil && got != tt.key) {
+			t.Errorf("key of %d characters: got %q, %v", len(tt.key), got, err)
+		}
+	}
+}
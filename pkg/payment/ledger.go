@@ -0,0 +1,44 @@
+package payment
+
+import (
+	"coins/pkg/money"
+	"time"
+)
+
+// System ledger accounts, money enters and leaves the system through them. IDs are negative so they never
+// clash with customer accounts, their balances aren't cached and can go below zero
+const (
+	// SystemFunding external source of top-ups
+	SystemFunding int64 = -1
+	// SystemExchange counterparty of both legs of cross-currency transfers
+	SystemExchange int64 = -2
+	// SystemPayout external destination of withdrawals
+	SystemPayout int64 = -3
+	// SystemPayoutPending holds funds of pending withdrawals until payout is confirmed or failed
+	SystemPayoutPending int64 = -4
+)
+
+// IsSystemAccount return true for system ledger accounts
+func IsSystemAccount(accountID int64) bool {
+	return accountID < 0
+}
+
+// Posting model, single double-entry journal entry. Negative amount debits the account, positive credits it,
+// postings of one transaction sum to zero in every currency
+type Posting struct {
+	ID            int64          `json:"id"`
+	TransactionID int64          `json:"transaction_id"`
+	AccountID     int64          `json:"account_id"`
+	Currency      money.Currency `json:"currency"`
+	Amount        money.Amount   `json:"amount"`
+	Date          time.Time      `json:"date"`
+}
+
+// BalanceCheck model, cached balance of account wallet compared with balance derived from postings
+type BalanceCheck struct {
+	AccountID  int64          `json:"account_id"`
+	Currency   money.Currency `json:"currency"`
+	Cached     money.Amount   `json:"cached"`
+	Ledger     money.Amount   `json:"ledger"`
+	Consistent bool           `json:"consistent"`
+}
@@ -1,18 +1,110 @@
 package payment
 
-import "time"
+import (
+	"coins/pkg/metadata"
+	"coins/pkg/money"
+	"time"
+)
 
-// Transaction model
+// Kind of transaction
+type Kind string
+
+// transaction kinds
+const (
+	KindTransfer   Kind = "transfer"
+	KindTopUp      Kind = "topup"
+	KindWithdrawal Kind = "withdrawal"
+	KindCapture    Kind = "capture"
+	KindRefund     Kind = "refund"
+	KindSweep      Kind = "sweep"
+)
+
+// Status of transaction
+type Status string
+
+// transaction statuses, only withdrawal stays pending until payout is confirmed
+const (
+	StatusPending   Status = "pending"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// Transaction model, `Amount` in `Currency` is debited from `From` and `ToAmount` in `ToCurrency` is credited to `To`.
+// Top-up comes from SystemFunding, withdrawal goes to SystemPayout and refund goes back from recipient to sender
+// of the original transaction. Internal transfer moves funds between accounts of the same customer
 type Transaction struct {
-	ID     int64     `json:"id"`
-	From   int64     `json:"from"`
-	To     int64     `json:"to"`
-	Amount float64   `json:"amount"`
-	Date   time.Time `json:"date"`
+	ID                    int64             `json:"id"`
+	Kind                  Kind              `json:"kind"`
+	Status                Status            `json:"status"`
+	From                  int64             `json:"from"`
+	To                    int64             `json:"to"`
+	Amount                money.Amount      `json:"amount"`
+	Currency              money.Currency    `json:"currency"`
+	ToAmount              money.Amount      `json:"to_amount"`
+	ToCurrency            money.Currency    `json:"to_currency"`
+	Rate                  money.Rate        `json:"rate"`
+	QuoteID               int64             `json:"quote_id,omitempty"`
+	OriginalTransactionID int64             `json:"original_transaction_id,omitempty"`
+	Internal              bool              `json:"internal"`
+	ExternalID            string            `json:"external_id,omitempty"`
+	Metadata              metadata.Metadata `json:"metadata,omitempty"`
+	Date                  time.Time         `json:"date"`
+}
+
+// Reference client data attached to transaction, ExternalID is optional unique client reference
+type Reference struct {
+	ExternalID string
+	Metadata   metadata.Metadata
+}
+
+func (t *Transaction) setReference(ref Reference) {
+	t.ExternalID = ref.ExternalID
+	t.Metadata = ref.Metadata
 }
 
-// Balance model
+// Balance model, one per account currency wallet. Ledger is the posted balance,
+// Available is what can be spent: ledger balance minus active holds
 type Balance struct {
-	AccountID int64   `json:"account_id"`
-	Balance   float64 `json:"balance"`
+	AccountID int64          `json:"account_id"`
+	Currency  money.Currency `json:"currency"`
+	Ledger    money.Amount   `json:"ledger"`
+	Available money.Amount   `json:"available"`
+}
+
+// Quote model, exchange rate locked until `ExpiresAt` for converting `Amount` into `ToAmount`. Only Owner,
+// identity of the client requested the quote, can transfer with it
+type Quote struct {
+	ID         int64          `json:"id"`
+	Currency   money.Currency `json:"currency"`
+	ToCurrency money.Currency `json:"to_currency"`
+	Amount     money.Amount   `json:"amount"`
+	ToAmount   money.Amount   `json:"to_amount"`
+	Rate       money.Rate     `json:"rate"`
+	ExpiresAt  time.Time      `json:"expires_at"`
+	Owner      string         `json:"-"`
+}
+
+// HoldStatus status of hold
+type HoldStatus string
+
+// hold statuses
+const (
+	HoldActive   HoldStatus = "active"
+	HoldCaptured HoldStatus = "captured"
+	HoldVoided   HoldStatus = "voided"
+	HoldExpired  HoldStatus = "expired"
+)
+
+// Hold model, funds of `From` reserved for transfer to `To`. Active hold reduces available balance
+// until it's captured, voided or expired
+type Hold struct {
+	ID            int64          `json:"id"`
+	From          int64          `json:"from"`
+	To            int64          `json:"to"`
+	Amount        money.Amount   `json:"amount"`
+	Currency      money.Currency `json:"currency"`
+	Status        HoldStatus     `json:"status"`
+	TransactionID int64          `json:"transaction_id,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	ExpiresAt     time.Time      `json:"expires_at"`
 }
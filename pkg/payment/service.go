@@ -2,57 +2,293 @@ package payment
 
 import (
 	"coins/pkg/account"
+	"coins/pkg/auth"
+	"coins/pkg/money"
 	"context"
 	"time"
 )
 
 // Service interface
 type Service interface {
-	GetBalance(context.Context, *account.Account) (*Balance, error)
-	ListTransactions(context.Context, *account.Account) ([]*Transaction, error)
-	Transfer(ctx context.Context, from, to *account.Account, amount float64) (*Transaction, error)
-	TopUp(ctx context.Context, a *account.Account, amount float64) (*Balance, error)
+	GetBalance(context.Context, *account.Account) ([]*Balance, error)
+	VerifyBalance(context.Context, *account.Account) ([]*BalanceCheck, error)
+	ListTransactions(context.Context, *account.Account, TransactionFilter) (*TransactionPage, error)
+	Transfer(ctx context.Context, from, to *account.Account, amount money.Amount, currency money.Currency, ref Reference) (*Transaction, error)
+	TransferFX(ctx context.Context, from, to *account.Account, amount money.Amount, currency, toCurrency money.Currency, quoteID int64, ref Reference) (*Transaction, error)
+	Quote(ctx context.Context, amount money.Amount, currency, toCurrency money.Currency) (*Quote, error)
+	TopUp(ctx context.Context, a *account.Account, amount money.Amount, currency money.Currency, ref Reference) (*Balance, error)
+	Withdraw(ctx context.Context, a *account.Account, amount money.Amount, currency money.Currency) (*Transaction, error)
+	ConfirmWithdrawal(ctx context.Context, id int64, status Status) (*Transaction, error)
+	Authorize(ctx context.Context, from, to *account.Account, amount money.Amount, currency money.Currency) (*Hold, error)
+	Capture(ctx context.Context, holdID int64, amount money.Amount) (*Transaction, error)
+	Void(ctx context.Context, holdID int64) (*Hold, error)
+	Refund(ctx context.Context, txID int64, amount money.Amount) (*Transaction, error)
+	Sweep(ctx context.Context, from, to int64) error
 }
 
 // Repository interface
 type Repository interface {
-	GetBalance(ctx context.Context, accountID int64) (*Balance, error)
-	ListTransactions(ctx context.Context, accountID int64) ([]*Transaction, error)
+	GetBalance(ctx context.Context, accountID int64) ([]*Balance, error)
+	VerifyBalance(ctx context.Context, accountID int64) ([]*BalanceCheck, error)
+	ListTransactions(ctx context.Context, accountID int64, filter TransactionFilter) (*TransactionPage, error)
 	Transfer(context.Context, *Transaction) (*Transaction, error)
-	TopUp(ctx context.Context, accountID int64, amount float64) (*Balance, error)
+	TopUp(context.Context, *Transaction) (*Balance, error)
+	Withdraw(context.Context, *Transaction) (*Transaction, error)
+	ConfirmWithdrawal(ctx context.Context, id int64, status Status) (*Transaction, error)
+	Authorize(context.Context, *Hold) (*Hold, error)
+	Capture(ctx context.Context, holdID int64, amount money.Amount) (*Transaction, error)
+	Void(ctx context.Context, holdID int64) (*Hold, error)
+	Refund(ctx context.Context, txID int64, amount money.Amount) (*Transaction, error)
+	Sweep(ctx context.Context, from, to int64) ([]*Transaction, error)
+	StoreQuote(context.Context, *Quote) (*Quote, error)
+	GetQuote(ctx context.Context, id int64) (*Quote, error)
+	PurgeIdempotencyKeys(context.Context) (int64, error)
+	// ExpireHolds mark active holds past their TTL as expired and return number of them
+	ExpireHolds(context.Context) (int64, error)
 }
 
+// FXRateProvider interface, source of exchange rates for cross-currency transfers
+type FXRateProvider interface {
+	// Rate return amount of `to` currency for one unit of `from` currency or ErrRateUnavailable
+	Rate(ctx context.Context, from, to money.Currency) (money.Rate, error)
+}
+
+// default service options
+const (
+	DefaultQuoteTTL = 30 * time.Second
+	DefaultHoldTTL  = 7 * 24 * time.Hour
+)
+
 type service struct {
-	repo Repository
+	repo     Repository
+	fx       FXRateProvider
+	quoteTTL time.Duration
+	holdTTL  time.Duration
+	limits   map[account.Tier]VelocityLimit
+}
+
+// Option - service option
+type Option func(*service)
+
+// WithQuoteTTL - set how long quoted exchange rate stays locked
+func WithQuoteTTL(d time.Duration) Option {
+	return func(s *service) {
+		s.quoteTTL = d
+	}
+}
+
+// WithHoldTTL - set how long hold reserves funds before it expires
+func WithHoldTTL(d time.Duration) Option {
+	return func(s *service) {
+		s.holdTTL = d
+	}
+}
+
+// TopUp - add funds to account balance in provided currency, recorded as top-up transaction from SystemFunding
+func (s *service) TopUp(ctx context.Context, a *account.Account, amount money.Amount, currency money.Currency, ref Reference) (*Balance, error) {
+	t := newTransaction(KindTopUp, SystemFunding, a.ID, amount, currency)
+	t.setReference(ref)
+	return s.repo.TopUp(ctx, t)
 }
 
-// TopUp - add funds to account balance
-func (s *service) TopUp(ctx context.Context, a *account.Account, amount float64) (*Balance, error) {
-	return s.repo.TopUp(ctx, a.ID, amount)
+// Withdraw - take funds out of the system, recorded as pending withdrawal transaction to SystemPayout.
+// Funds are held until payout is confirmed by ConfirmWithdrawal, raise ErrInsufficientFunds when account doesn't have enough funds
+// and ErrLimitExceeded when withdrawal exceeds daily outflow of account tier
+func (s *service) Withdraw(ctx context.Context, a *account.Account, amount money.Amount, currency money.Currency) (*Transaction, error) {
+	t := newTransaction(KindWithdrawal, a.ID, SystemPayout, amount, currency)
+	t.Status = StatusPending
+	return s.repo.Withdraw(s.withVelocityLimit(ctx, a), t)
 }
 
-// GetBalance - return account balance or ErrNotFound if such account doesn't exists
-func (s *service) GetBalance(ctx context.Context, a *account.Account) (*Balance, error) {
+// ConfirmWithdrawal - finish pending withdrawal with result of external payout, StatusCompleted releases held funds
+// to SystemPayout and StatusFailed returns them to the account. Raise ErrNotPending when withdrawal already finished
+func (s *service) ConfirmWithdrawal(ctx context.Context, id int64, status Status) (*Transaction, error) {
+	return s.repo.ConfirmWithdrawal(ctx, id, status)
+}
+
+// GetBalance - return balances of every account currency wallet or ErrNotFound if such account doesn't exists
+func (s *service) GetBalance(ctx context.Context, a *account.Account) ([]*Balance, error) {
 	return s.repo.GetBalance(ctx, a.ID)
 }
 
-// ListTransactions - return list of all account transactions or ErrNotFound if such account doesn't exists
-func (s *service) ListTransactions(ctx context.Context, a *account.Account) ([]*Transaction, error) {
-	return s.repo.ListTransactions(ctx, a.ID)
+// VerifyBalance - compare cached balances of account with balances derived from ledger postings
+func (s *service) VerifyBalance(ctx context.Context, a *account.Account) ([]*BalanceCheck, error) {
+	return s.repo.VerifyBalance(ctx, a.ID)
+}
+
+// ListTransactions - return page of account transactions matching filter, page size is DefaultPageSize when not set
+// and can't exceed MaxPageSize
+func (s *service) ListTransactions(ctx context.Context, a *account.Account, filter TransactionFilter) (*TransactionPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageSize
+	}
+	if filter.Limit > MaxPageSize {
+		filter.Limit = MaxPageSize
+	}
+	if filter.Order == "" {
+		filter.Order = OrderAsc
+	}
+	return s.repo.ListTransactions(ctx, a.ID, filter)
 }
 
-// Transfer -  transfer funds from one account to an other, raise ErrInsufficientFunds when `from` account doesn't have enough funds
-func (s *service) Transfer(ctx context.Context, from, to *account.Account, amount float64) (*Transaction, error) {
+// Transfer -  transfer funds from one account to an other in the same currency, raise ErrInsufficientFunds when `from` account doesn't have enough funds
+// and ErrLimitExceeded when transfer breaks velocity limit of `from` account tier
+func (s *service) Transfer(ctx context.Context, from, to *account.Account, amount money.Amount, currency money.Currency, ref Reference) (*Transaction, error) {
+	t := newTransaction(KindTransfer, from.ID, to.ID, amount, currency)
+	t.Internal = from.SameCustomer(to)
+	t.setReference(ref)
+	return s.repo.Transfer(s.withVelocityLimit(ctx, from), t)
+}
+
+// TransferFX - transfer funds debiting `amount` in `currency` and crediting converted amount in `toCurrency`.
+// When quoteID provided the locked rate of the quote is used, otherwise the current rate of FXRateProvider
+func (s *service) TransferFX(ctx context.Context, from, to *account.Account, amount money.Amount, currency, toCurrency money.Currency, quoteID int64, ref Reference) (*Transaction, error) {
+	var rate money.Rate
+	if quoteID != 0 {
+		q, err := s.repo.GetQuote(ctx, quoteID)
+		if err != nil {
+			return nil, err
+		}
+		// quote of another client isn't disclosed
+		if owner, _ := auth.Identity(ctx); q.Owner != owner {
+			return nil, ErrQuoteNotFound{ID: quoteID}
+		}
+		// expiry is checked when repository applies the quote, after retry of idempotent transfer is answered
+		if q.Currency != currency || q.ToCurrency != toCurrency || q.Amount != amount {
+			return nil, ErrQuoteMismatch{ID: quoteID}
+		}
+		rate = q.Rate
+	} else {
+		var err error
+		if rate, err = s.rate(ctx, currency, toCurrency); err != nil {
+			return nil, err
+		}
+	}
+
+	toAmount, err := amount.Convert(rate)
+	if err != nil {
+		return nil, err
+	}
+
 	t := &Transaction{
-		From:   from.ID,
-		To:     to.ID,
-		Amount: amount,
-		Date:   time.Now().UTC(),
+		Kind:       KindTransfer,
+		Status:     StatusCompleted,
+		From:       from.ID,
+		To:         to.ID,
+		Amount:     amount,
+		Currency:   currency,
+		ToAmount:   toAmount,
+		ToCurrency: toCurrency,
+		Rate:       rate,
+		QuoteID:    quoteID,
+		Internal:   from.SameCustomer(to),
+		Date:       time.Now().UTC(),
+	}
+	t.setReference(ref)
+	return s.repo.Transfer(s.withVelocityLimit(ctx, from), t)
+}
+
+// Quote - lock current exchange rate for converting amount from `currency` into `toCurrency` for the quote TTL,
+// only the authenticated client requested the quote can transfer with it
+func (s *service) Quote(ctx context.Context, amount money.Amount, currency, toCurrency money.Currency) (*Quote, error) {
+	rate, err := s.rate(ctx, currency, toCurrency)
+	if err != nil {
+		return nil, err
+	}
+	toAmount, err := amount.Convert(rate)
+	if err != nil {
+		return nil, err
+	}
+	q := &Quote{
+		Currency:   currency,
+		ToCurrency: toCurrency,
+		Amount:     amount,
+		ToAmount:   toAmount,
+		Rate:       rate,
+		ExpiresAt:  time.Now().UTC().Add(s.quoteTTL),
+	}
+	q.Owner, _ = auth.Identity(ctx)
+	return s.repo.StoreQuote(ctx, q)
+}
+
+func (s *service) rate(ctx context.Context, from, to money.Currency) (money.Rate, error) {
+	if from == to {
+		return money.OneRate, nil
+	}
+	if s.fx == nil {
+		return 0, ErrRateUnavailable{From: from, To: to}
+	}
+	return s.fx.Rate(ctx, from, to)
+}
+
+// Authorize - place hold on `amount` of `from` account for later capture to `to` account. Hold reduces available
+// but not ledger balance and expires after hold TTL, raise ErrInsufficientFunds when available balance isn't enough
+// and ErrLimitExceeded when hold exceeds daily outflow of `from` account tier
+func (s *service) Authorize(ctx context.Context, from, to *account.Account, amount money.Amount, currency money.Currency) (*Hold, error) {
+	now := time.Now().UTC()
+	h := &Hold{
+		From:      from.ID,
+		To:        to.ID,
+		Amount:    amount,
+		Currency:  currency,
+		Status:    HoldActive,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.holdTTL),
+	}
+	return s.repo.Authorize(s.withVelocityLimit(ctx, from), h)
+}
+
+// Capture - transfer full(zero amount) or partial amount of hold, the rest of the hold is released
+func (s *service) Capture(ctx context.Context, holdID int64, amount money.Amount) (*Transaction, error) {
+	return s.repo.Capture(ctx, holdID, amount)
+}
+
+// Void - release hold without transferring funds
+func (s *service) Void(ctx context.Context, holdID int64) (*Hold, error) {
+	return s.repo.Void(ctx, holdID)
+}
+
+// Refund - create compensating transaction returning full(zero amount) or partial amount of transfer to its sender.
+// Several partial refunds are allowed up to the original amount, raise ErrInsufficientFunds when recipient doesn't
+// have enough funds anymore
+func (s *service) Refund(ctx context.Context, txID int64, amount money.Amount) (*Transaction, error) {
+	return s.repo.Refund(ctx, txID, amount)
+}
+
+// newTransaction build completed same currency transaction
+func newTransaction(kind Kind, from, to int64, amount money.Amount, currency money.Currency) *Transaction {
+	return &Transaction{
+		Kind:       kind,
+		Status:     StatusCompleted,
+		From:       from,
+		To:         to,
+		Amount:     amount,
+		Currency:   currency,
+		ToAmount:   amount,
+		ToCurrency: currency,
+		Rate:       money.OneRate,
+		Date:       time.Now().UTC(),
+	}
+}
+
+// NewService - build new service, fx used to price cross-currency transfers
+func NewService(repo Repository, fx FXRateProvider, opts ...Option) Service {
+	s := &service{
+		repo:     repo,
+		fx:       fx,
+		quoteTTL: DefaultQuoteTTL,
+		holdTTL:  DefaultHoldTTL,
+		limits:   DefaultVelocityLimits,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
-	return s.repo.Transfer(ctx, t)
+	return s
 }
 
-// NewService - build new service
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+// Sweep - transfer all funds of closed account to another account, implements account.Sweeper.
+// With zero `to` raise account.ErrNonZeroBalance when account has any funds, active holds or pending withdrawals
+func (s *service) Sweep(ctx context.Context, from, to int64) error {
+	_, err := s.repo.Sweep(ctx, from, to)
+	return err
 }
@@ -0,0 +1,256 @@
+package payment
+
+import (
+	"coins/pkg/account"
+	"coins/pkg/auth"
+	"coins/pkg/metadata"
+	"coins/pkg/money"
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// stubRepo keep quotes in memory and record transactions and filter passed to the repository, methods not needed
+// by tests aren't implemented
+type stubRepo struct {
+	Repository
+	quotes map[int64]*Quote
+	txs    []*Transaction
+	filter TransactionFilter
+	ctx    context.Context
+}
+
+func newStubRepo() *stubRepo {
+	return &stubRepo{quotes: make(map[int64]*Quote)}
+}
+
+func (r *stubRepo) StoreQuote(_ context.Context, q *Quote) (*Quote, error) {
+	q.ID = int64(len(r.quotes) + 1)
+	r.quotes[q.ID] = q
+	return q, nil
+}
+
+func (r *stubRepo) GetQuote(_ context.Context, id int64) (*Quote, error) {
+	q, ok := r.quotes[id]
+	if !ok {
+		return nil, ErrQuoteNotFound{ID: id}
+	}
+	return q, nil
+}
+
+func (r *stubRepo) Transfer(ctx context.Context, t *Transaction) (*Transaction, error) {
+	r.ctx = ctx
+	t.ID = int64(len(r.txs) + 1)
+	r.txs = append(r.txs, t)
+	return t, nil
+}
+
+func (r *stubRepo) TopUp(ctx context.Context, t *Transaction) (*Balance, error) {
+	r.ctx = ctx
+	t.ID = int64(len(r.txs) + 1)
+	r.txs = append(r.txs, t)
+	return &Balance{Currency: t.Currency, Ledger: t.Amount, Available: t.Amount}, nil
+}
+
+func (r *stubRepo) Withdraw(ctx context.Context, t *Transaction) (*Transaction, error) {
+	r.ctx = ctx
+	t.ID = int64(len(r.txs) + 1)
+	r.txs = append(r.txs, t)
+	return t, nil
+}
+
+func (r *stubRepo) Authorize(ctx context.Context, h *Hold) (*Hold, error) {
+	r.ctx = ctx
+	h.ID = 1
+	return h, nil
+}
+
+func (r *stubRepo) ListTransactions(_ context.Context, _ int64, filter TransactionFilter) (*TransactionPage, error) {
+	r.filter = filter
+	return &TransactionPage{}, nil
+}
+
+func (r *stubRepo) ConfirmWithdrawal(_ context.Context, id int64, status Status) (*Transaction, error) {
+	for _, t := range r.txs {
+		if t.ID == id {
+			t.Status = status
+			return t, nil
+		}
+	}
+	return nil, ErrTransactionNotFound{ID: id}
+}
+
+type stubFX map[[2]money.Currency]string
+
+func (fx stubFX) Rate(_ context.Context, from, to money.Currency) (money.Rate, error) {
+	s, ok := fx[[2]money.Currency{from, to}]
+	if !ok {
+		return 0, ErrRateUnavailable{From: from, To: to}
+	}
+	return money.ParseRate(s)
+}
+
+type stubAuthn struct {
+	key *auth.Key
+}
+
+func (a stubAuthn) Authenticate(context.Context, string) (*auth.Key, error) {
+	return a.key, nil
+}
+
+func (a stubAuthn) VerifyToken(string) (*auth.Claims, error) {
+	return nil, auth.ErrUnauthenticated{}
+}
+
+// asKey return context of request authenticated by API key with ID
+func asKey(t *testing.T, id int64) context.Context {
+	t.Helper()
+	r := httptest.NewRequest("POST", "/", nil)
+	r.Header.Set(auth.APIKeyHeader, "token")
+	var ctx context.Context
+	authn := stubAuthn{key: &auth.Key{ID: id, Scopes: []auth.Scope{auth.ScopePaymentsWrite}}}
+	_, err := auth.Require(authn, auth.ScopePaymentsWrite)(func(c context.Context, _ interface{}) (interface{}, error) {
+		ctx = c
+		return nil, nil
+	})(auth.HTTPToContext(context.Background(), r), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
+
+func testAccount(id int64) *account.Account {
+	a := account.New(0, "Alice", "Smith")
+	a.ID = id
+	return a
+}
+
+func TestTransferFXQuoteOwner(t *testing.T) {
+	repo := newStubRepo()
+	s := NewService(repo, stubFX{{"EUR", "USD"}: "1.1"})
+	alice, bob := asKey(t, 1), asKey(t, 2)
+
+	q, err := s.Quote(alice, 10000, "EUR", "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Owner != "key:1" || q.ToAmount != 11000 {
+		t.Fatalf("got quote %+v", q)
+	}
+
+	if _, err := s.TransferFX(bob, testAccount(3), testAccount(4), 10000, "EUR", "USD", q.ID, Reference{}); err != (ErrQuoteNotFound{ID: q.ID}) {
+		t.Fatalf("quote of another client: got %v, want ErrQuoteNotFound", err)
+	}
+	if _, err := s.TransferFX(context.Background(), testAccount(3), testAccount(4), 10000, "EUR", "USD", q.ID, Reference{}); err != (ErrQuoteNotFound{ID: q.ID}) {
+		t.Fatalf("quote without client: got %v, want ErrQuoteNotFound", err)
+	}
+	if _, err := s.TransferFX(alice, testAccount(3), testAccount(4), 5000, "EUR", "USD", q.ID, Reference{}); err != (ErrQuoteMismatch{ID: q.ID}) {
+		t.Fatalf("other amount: got %v, want ErrQuoteMismatch", err)
+	}
+	tx, err := s.TransferFX(alice, testAccount(3), testAccount(4), 10000, "EUR", "USD", q.ID, Reference{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.QuoteID != q.ID || tx.ToAmount != 11000 || tx.Rate != q.Rate {
+		t.Fatalf("got transaction %+v", tx)
+	}
+}
+
+func TestTransferSameCurrency(t *testing.T) {
+	repo := newStubRepo()
+	s := NewService(repo, nil)
+	tx, err := s.Transfer(context.Background(), testAccount(1), testAccount(2), 1050, "GBP", Reference{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Transaction{Kind: KindTransfer, Status: StatusCompleted, From: 1, To: 2, Amount: 1050, Currency: "GBP", ToAmount: 1050, ToCurrency: "GBP", Rate: money.OneRate}
+	got := *tx
+	got.ID, got.Date = 0, want.Date
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestTopUpRecordsTransaction(t *testing.T) {
+	repo := newStubRepo()
+	s := NewService(repo, nil)
+	ref := Reference{ExternalID: "inv-1", Metadata: metadata.Metadata{"order": "42"}}
+	if _, err := s.TopUp(context.Background(), testAccount(1), 1000, "EUR", ref); err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.txs) != 1 {
+		t.Fatalf("got %d transactions, want 1", len(repo.txs))
+	}
+	tx := repo.txs[0]
+	if tx.Kind != KindTopUp || tx.From != SystemFunding || tx.To != 1 || tx.Amount != 1000 || tx.Currency != "EUR" || tx.Status != StatusCompleted {
+		t.Errorf("got %+v", tx)
+	}
+	if tx.ExternalID != "inv-1" || tx.Metadata["order"] != "42" {
+		t.Errorf("reference isn't recorded: %+v", tx)
+	}
+}
+
+func TestWithdrawIsPending(t *testing.T) {
+	repo := newStubRepo()
+	s := NewService(repo, nil)
+	tx, err := s.Withdraw(context.Background(), testAccount(1), 2500, "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Kind != KindWithdrawal || tx.Status != StatusPending || tx.From != 1 || tx.To != SystemPayout || tx.Amount != 2500 || tx.Currency != "USD" {
+		t.Errorf("got %+v", tx)
+	}
+	l, ok := VelocityLimitFromContext(repo.ctx)
+	if !ok || l.TransfersPerMinute != DefaultVelocityLimits[account.TierStandard].TransfersPerMinute {
+		t.Errorf("got velocity limit %+v, want limit of standard tier", l)
+	}
+
+	if _, err := s.ConfirmWithdrawal(context.Background(), tx.ID, StatusFailed); err != nil {
+		t.Fatal(err)
+	}
+	if tx.Status != StatusFailed {
+		t.Errorf("got status %s, want %s", tx.Status, StatusFailed)
+	}
+}
+
+func TestAuthorizeHoldTTL(t *testing.T) {
+	repo := newStubRepo()
+	s := NewService(repo, nil, WithHoldTTL(time.Hour))
+	h, err := s.Authorize(context.Background(), testAccount(1), testAccount(2), 500, "EUR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Status != HoldActive || h.From != 1 || h.To != 2 || h.Amount != 500 || h.Currency != "EUR" {
+		t.Errorf("got %+v", h)
+	}
+	if ttl := h.ExpiresAt.Sub(h.CreatedAt); ttl != time.Hour {
+		t.Errorf("got TTL %s, want %s", ttl, time.Hour)
+	}
+	if _, ok := VelocityLimitFromContext(repo.ctx); !ok {
+		t.Error("velocity limit of account tier isn't passed to the repository")
+	}
+}
+
+func TestListTransactionsPageSize(t *testing.T) {
+	tests := []struct {
+		limit int
+		order Order
+		want  TransactionFilter
+	}{
+		{0, "", TransactionFilter{Limit: DefaultPageSize, Order: OrderAsc}},
+		{10, OrderDesc, TransactionFilter{Limit: 10, Order: OrderDesc}},
+		{MaxPageSize + 1, "", TransactionFilter{Limit: MaxPageSize, Order: OrderAsc}},
+	}
+	for _, tt := range tests {
+		repo := newStubRepo()
+		s := NewService(repo, nil)
+		if _, err := s.ListTransactions(context.Background(), testAccount(1), TransactionFilter{Limit: tt.limit, Order: tt.order}); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(repo.filter, tt.want) {
+			t.Errorf("limit %d: got %+v, want %+v", tt.limit, repo.filter, tt.want)
+		}
+	}
+}
@@ -2,12 +2,26 @@ package payment
 
 import (
 	"coins/pkg/account"
+	"coins/pkg/auth"
+	"coins/pkg/metadata"
+	"coins/pkg/money"
+	"coins/pkg/problem"
+	"coins/pkg/ratelimit"
+	"coins/pkg/response"
+	"coins/pkg/signature"
+	"coins/pkg/validation"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/go-kit/kit/log"
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/gorilla/mux"
 )
@@ -20,68 +34,130 @@ func (e errBadRequest) Error() string {
 	return e.Msg
 }
 
-// MakeHandler build handlers for payment transport
-func MakeHandler(ps Service, as account.Service) http.Handler {
+// Code implements problem.Coder
+func (e errBadRequest) Code() string {
+	return problem.CodeInvalidParameter
+}
+
+// MakeHandler build handlers for payment transport of API v2, breaking changes land here while v1 stays frozen
+func MakeHandler(ps Service, as account.Service, authn auth.Authenticator, rl *ratelimit.Limiter, sv *signature.Verifier, logger log.Logger) http.Handler {
+	const prefix = "/v2/payment/"
+	enc := response.NewEncoder(logger, false, 0)
 	opts := []kithttp.ServerOption{
-		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerBefore(kithttp.PopulateRequestContext, problem.PopulateRequestID, auth.HTTPToContext),
+		kithttp.ServerAfter(problem.SetRequestIDHeader),
+		kithttp.ServerErrorEncoder(enc.EncodeError),
 	}
+	read, write := rl.Wrap(auth.RequireOwner(authn, auth.ScopePaymentsRead)), rl.Wrap(auth.Require(authn, auth.ScopePaymentsWrite))
+	ownWrite := rl.Wrap(auth.RequireOwner(authn, auth.ScopePaymentsWrite))
+	// only the payout provider reports results of withdrawals
+	payout := rl.Wrap(auth.Require(authn, auth.ScopePayoutsWrite))
+	// transfer and topup of partners must be signed, signature is checked after the client is authenticated
+	signedOpts := append([]kithttp.ServerOption{kithttp.ServerBefore(signature.HTTPToContext(sv))}, opts...)
+	signed := signature.Middleware(sv)
 
 	getBalanceHandler := kithttp.NewServer(
-		makeGetBalanceEndpoint(ps, as),
+		read(validation.Middleware(makeGetBalanceEndpoint(ps, as))),
 		decodeGetBalanceRequest,
-		encodeResponse,
+		enc.OK(),
+		opts...,
+	)
+
+	verifyBalanceHandler := kithttp.NewServer(
+		read(validation.Middleware(makeVerifyBalanceEndpoint(ps, as))),
+		decodeGetBalanceRequest,
+		enc.OK(),
 		opts...,
 	)
 
 	listTransactionsHandler := kithttp.NewServer(
-		makeListTransactionsEndpoint(ps, as),
+		read(validation.Middleware(makeListTransactionsEndpoint(ps, as))),
 		decodeListTransactionsRequest,
-		encodeResponse,
+		enc.OK(),
 		opts...,
 	)
 
 	transferHandler := kithttp.NewServer(
-		makeTransferEndpoint(ps, as),
+		ownWrite(signed(validation.Middleware(makeTransferEndpoint(ps, as)))),
 		decodeTransferRequest,
-		encodeResponse,
+		enc.Created(),
+		signedOpts...,
+	)
+
+	quoteHandler := kithttp.NewServer(
+		ownWrite(validation.Middleware(makeQuoteEndpoint(ps))),
+		decodeQuoteRequest,
+		enc.Created(),
 		opts...,
 	)
 
 	topUpHandler := kithttp.NewServer(
-		makeTopUpEndpoint(ps, as),
+		write(signed(validation.Middleware(makeTopUpEndpoint(ps, as)))),
 		decodeTopUpRequest,
-		encodeResponse,
+		enc.Created(),
+		signedOpts...,
+	)
+
+	withdrawHandler := kithttp.NewServer(
+		write(validation.Middleware(makeWithdrawEndpoint(ps, as))),
+		decodeWithdrawRequest,
+		enc.Created(),
+		opts...,
+	)
+
+	confirmWithdrawalHandler := kithttp.NewServer(
+		payout(validation.Middleware(makeConfirmWithdrawalEndpoint(ps))),
+		decodeConfirmWithdrawalRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	authorizeHandler := kithttp.NewServer(
+		write(validation.Middleware(makeAuthorizeEndpoint(ps, as))),
+		decodeAuthorizeRequest,
+		enc.Created(),
+		opts...,
+	)
+
+	captureHandler := kithttp.NewServer(
+		write(validation.Middleware(makeCaptureEndpoint(ps))),
+		decodeCaptureRequest,
+		enc.Created(),
+		opts...,
+	)
+
+	voidHandler := kithttp.NewServer(
+		write(validation.Middleware(makeVoidEndpoint(ps))),
+		decodeVoidRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	refundHandler := kithttp.NewServer(
+		write(validation.Middleware(makeRefundEndpoint(ps))),
+		decodeRefundRequest,
+		enc.Created(),
 		opts...,
 	)
 
 	r := mux.NewRouter()
 
-	r.Handle("/payment/v1/balance/{id}", getBalanceHandler).Methods("GET")
-	r.Handle("/payment/v1/transactions/{id}", listTransactionsHandler).Methods("GET")
-	r.Handle("/payment/v1/transfer", transferHandler).Methods("POST")
-	r.Handle("/payment/v1/topup", topUpHandler).Methods("POST")
+	r.Handle(prefix+"balance/{id}", getBalanceHandler).Methods("GET")
+	r.Handle(prefix+"balance/{id}/verify", verifyBalanceHandler).Methods("GET")
+	r.Handle(prefix+"transactions/{id}", listTransactionsHandler).Methods("GET")
+	r.Handle(prefix+"transactions/{id}/refund", refundHandler).Methods("POST")
+	r.Handle(prefix+"transfer", signature.LimitBody(transferHandler)).Methods("POST")
+	r.Handle(prefix+"quote", quoteHandler).Methods("POST")
+	r.Handle(prefix+"topup", signature.LimitBody(topUpHandler)).Methods("POST")
+	r.Handle(prefix+"withdraw", withdrawHandler).Methods("POST")
+	r.Handle(prefix+"withdrawals/{id}/callback", confirmWithdrawalHandler).Methods("POST")
+	r.Handle(prefix+"holds", authorizeHandler).Methods("POST")
+	r.Handle(prefix+"holds/{id}/capture", captureHandler).Methods("POST")
+	r.Handle(prefix+"holds/{id}/void", voidHandler).Methods("POST")
 
 	return r
 }
 
-// encode errors from business-logic
-func encodeError(_ context.Context, err error, w http.ResponseWriter) {
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	switch err.(type) {
-	case account.ErrNotFound:
-		w.WriteHeader(http.StatusNotFound)
-	case errBadRequest:
-		w.WriteHeader(http.StatusBadRequest)
-	case ErrInsufficientFunds:
-		w.WriteHeader(http.StatusBadRequest)
-	default:
-		w.WriteHeader(http.StatusInternalServerError)
-	}
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"error": err.Error(),
-	})
-}
-
 func decodeGetBalanceRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	vars := mux.Vars(r)
 	idStr, ok := vars["id"]
@@ -105,43 +181,281 @@ func decodeListTransactionsRequest(_ context.Context, r *http.Request) (interfac
 	if err != nil {
 		return nil, errBadRequest{Msg: fmt.Sprintf("id param must be int")}
 	}
-	return listTransactionsRequest{ID: id}, nil
+
+	var f TransactionFilter
+	q := r.URL.Query()
+	if v := q.Get("cursor"); v != "" {
+		if f.After, err = decodeCursor(v); err != nil {
+			return nil, errBadRequest{Msg: fmt.Sprintf("cursor param is invalid")}
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		if f.Limit, err = strconv.Atoi(v); err != nil || f.Limit <= 0 {
+			return nil, errBadRequest{Msg: fmt.Sprintf("limit param must be positive int")}
+		}
+	}
+	switch o := Order(q.Get("order")); o {
+	case "", OrderAsc, OrderDesc:
+		f.Order = o
+	default:
+		return nil, errBadRequest{Msg: fmt.Sprintf("order param must be %s or %s", OrderAsc, OrderDesc)}
+	}
+	if v := q.Get("since"); v != "" {
+		if f.Since, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, errBadRequest{Msg: fmt.Sprintf("since param must be RFC3339 date")}
+		}
+	}
+	if v := q.Get("until"); v != "" {
+		if f.Until, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, errBadRequest{Msg: fmt.Sprintf("until param must be RFC3339 date")}
+		}
+	}
+	switch d := Direction(q.Get("direction")); d {
+	case "", DirectionIncoming, DirectionOutgoing:
+		f.Direction = d
+	default:
+		return nil, errBadRequest{Msg: fmt.Sprintf("direction param must be %s or %s", DirectionIncoming, DirectionOutgoing)}
+	}
+	if v := q.Get("counterparty"); v != "" {
+		if f.Counterparty, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, errBadRequest{Msg: fmt.Sprintf("counterparty param must be int")}
+		}
+	}
+	if v := q.Get("min_amount"); v != "" {
+		a, err := money.Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		f.MinAmount = &a
+	}
+	f.ExternalID = q.Get("external_id")
+	f.Metadata = metadata.FilterFromQuery(q)
+	if v := q.Get("max_amount"); v != "" {
+		a, err := money.Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		f.MaxAmount = &a
+	}
+	return listTransactionsRequest{ID: id, Filter: f}, nil
+}
+
+// encodeCursor return opaque cursor string, empty for nil cursor
+func encodeCursor(c *Cursor) string {
+	if c == nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", c.Date.UnixNano(), c.ID)))
+}
+
+func decodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(string(b), ":", 2)
+	if len(parts) != 2 {
+		return nil, errors.New("malformed cursor")
+	}
+	nsec, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &Cursor{Date: time.Unix(0, nsec).UTC(), ID: id}, nil
 }
 
 func decodeTransferRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	var body struct {
-		From   int64   `json:"from"`
-		To     int64   `json:"to"`
-		Amount float64 `json:"amount"`
+		From       int64             `json:"from"`
+		To         int64             `json:"to"`
+		Amount     money.Amount      `json:"amount"`
+		Currency   money.Currency    `json:"currency"`
+		ToCurrency money.Currency    `json:"to_currency"`
+		Convert    bool              `json:"convert"`
+		QuoteID    int64             `json:"quote_id"`
+		ExternalID string            `json:"external_id"`
+		Metadata   metadata.Metadata `json:"metadata"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		return nil, err
 	}
-	return transferRequest{From: body.From, To: body.To, Amount: body.Amount}, nil
+	key, err := decodeIdempotencyKey(r)
+	if err != nil {
+		return nil, err
+	}
+	return transferRequest{
+		From:           body.From,
+		To:             body.To,
+		Amount:         body.Amount,
+		Currency:       body.Currency,
+		ToCurrency:     body.ToCurrency,
+		Convert:        body.Convert,
+		QuoteID:        body.QuoteID,
+		Reference:      Reference{ExternalID: body.ExternalID, Metadata: body.Metadata},
+		IdempotencyKey: key,
+	}, nil
+}
+
+func decodeQuoteRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var body struct {
+		Amount     money.Amount   `json:"amount"`
+		Currency   money.Currency `json:"currency"`
+		ToCurrency money.Currency `json:"to_currency"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return quoteRequest{Amount: body.Amount, Currency: body.Currency, ToCurrency: body.ToCurrency}, nil
 }
 
 func decodeTopUpRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	var body struct {
-		AccountID int64   `json:"account_id"`
-		Amount    float64 `json:"amount"`
+		AccountID  int64             `json:"account_id"`
+		Amount     money.Amount      `json:"amount"`
+		Currency   money.Currency    `json:"currency"`
+		ExternalID string            `json:"external_id"`
+		Metadata   metadata.Metadata `json:"metadata"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	key, err := decodeIdempotencyKey(r)
+	if err != nil {
+		return nil, err
+	}
+	return topUpRequest{
+		AccountID:      body.AccountID,
+		Amount:         body.Amount,
+		Currency:       body.Currency,
+		Reference:      Reference{ExternalID: body.ExternalID, Metadata: body.Metadata},
+		IdempotencyKey: key,
+	}, nil
+}
+
+func decodeWithdrawRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var body struct {
+		AccountID int64          `json:"account_id"`
+		Amount    money.Amount   `json:"amount"`
+		Currency  money.Currency `json:"currency"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	key, err := decodeIdempotencyKey(r)
+	if err != nil {
+		return nil, err
+	}
+	return withdrawRequest{
+		AccountID:      body.AccountID,
+		Amount:         body.Amount,
+		Currency:       body.Currency,
+		IdempotencyKey: key,
+	}, nil
+}
+
+func decodeConfirmWithdrawalRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	idStr, ok := vars["id"]
+	if !ok {
+		return nil, errBadRequest{Msg: fmt.Sprintf("id param required")}
+	}
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return nil, errBadRequest{Msg: fmt.Sprintf("id param must be int")}
+	}
+
+	var body struct {
+		Status Status `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	if body.Status != StatusCompleted && body.Status != StatusFailed {
+		return nil, errBadRequest{Msg: fmt.Sprintf("status param must be %s or %s", StatusCompleted, StatusFailed)}
+	}
+	return confirmWithdrawalRequest{ID: id, Status: body.Status}, nil
+}
+
+func decodeAuthorizeRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var body struct {
+		From     int64          `json:"from"`
+		To       int64          `json:"to"`
+		Amount   money.Amount   `json:"amount"`
+		Currency money.Currency `json:"currency"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		return nil, err
 	}
-	return topUpRequest{AccountID: body.AccountID, Amount: body.Amount}, nil
+	return authorizeRequest{From: body.From, To: body.To, Amount: body.Amount, Currency: body.Currency}, nil
+}
+
+func decodeCaptureRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := decodeID(r)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Amount money.Amount `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return captureRequest{HoldID: id, Amount: body.Amount}, nil
 }
 
-func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
-	if e, ok := response.(errorer); ok && e.error() != nil {
-		encodeError(ctx, e.error(), w)
-		return nil
+func decodeVoidRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := decodeID(r)
+	if err != nil {
+		return nil, err
 	}
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	return json.NewEncoder(w).Encode(response)
+	return voidRequest{HoldID: id}, nil
 }
 
-type errorer interface {
-	error() error
+func decodeRefundRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := decodeID(r)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Amount money.Amount `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+		return nil, err
+	}
+	key, err := decodeIdempotencyKey(r)
+	if err != nil {
+		return nil, err
+	}
+	return refundRequest{TransactionID: id, Amount: body.Amount, IdempotencyKey: key}, nil
+}
+
+func decodeID(r *http.Request) (int64, error) {
+	vars := mux.Vars(r)
+	idStr, ok := vars["id"]
+	if !ok {
+		return 0, errBadRequest{Msg: fmt.Sprintf("id param required")}
+	}
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return 0, errBadRequest{Msg: fmt.Sprintf("id param must be int")}
+	}
+	return id, nil
+}
+
+// decodeIdempotencyKey read optional Idempotency-Key header, endpoint binds it to the decoded request
+func decodeIdempotencyKey(r *http.Request) (string, error) {
+	key := r.Header.Get("Idempotency-Key")
+	if len(key) > maxIdempotencyKeyLength {
+		return "", errBadRequest{Msg: fmt.Sprintf("Idempotency-Key header must be at most %d characters", maxIdempotencyKeyLength)}
+	}
+	return key, nil
 }
@@ -0,0 +1,372 @@
+package payment
+
+import (
+	"coins/pb"
+	"coins/pkg/account"
+	"coins/pkg/auth"
+	"coins/pkg/metadata"
+	"coins/pkg/money"
+	"coins/pkg/problem"
+	"coins/pkg/ratelimit"
+	"coins/pkg/response"
+	"coins/pkg/signature"
+	"coins/pkg/validation"
+	"context"
+	"fmt"
+
+	"github.com/go-kit/kit/log"
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+	"github.com/golang/protobuf/ptypes"
+)
+
+type grpcServer struct {
+	getBalance       kitgrpc.Handler
+	listTransactions kitgrpc.Handler
+	transfer         kitgrpc.Handler
+	topUp            kitgrpc.Handler
+	withdraw         kitgrpc.Handler
+	refund           kitgrpc.Handler
+	errors           *problem.Encoder
+}
+
+// MakeGRPCServer build gRPC server of payment service on the same endpoints as HTTP transport. gRPC calls
+// aren't signed, so when signing is enabled API keys can't transfer and top up over gRPC
+func MakeGRPCServer(ps Service, as account.Service, authn auth.Authenticator, rl *ratelimit.Limiter, sv *signature.Verifier, logger log.Logger) pb.PaymentsServer {
+	opts := []kitgrpc.ServerOption{
+		kitgrpc.ServerBefore(problem.PopulateGRPCRequestID, auth.GRPCToContext),
+		kitgrpc.ServerAfter(problem.SetGRPCRequestID),
+	}
+	read, write := rl.Wrap(auth.RequireOwner(authn, auth.ScopePaymentsRead)), rl.Wrap(auth.Require(authn, auth.ScopePaymentsWrite))
+	ownWrite := rl.Wrap(auth.RequireOwner(authn, auth.ScopePaymentsWrite))
+	unsigned := signature.RejectKeys(sv)
+	return &grpcServer{
+		getBalance: kitgrpc.NewServer(
+			read(validation.Middleware(makeGetBalanceEndpoint(ps, as))),
+			decodeGRPCBalanceRequest,
+			encodeGRPCBalanceResponse,
+			opts...,
+		),
+		listTransactions: kitgrpc.NewServer(
+			read(validation.Middleware(makeListTransactionsEndpoint(ps, as))),
+			decodeGRPCListTransactionsRequest,
+			encodeGRPCListTransactionsResponse,
+			opts...,
+		),
+		transfer: kitgrpc.NewServer(
+			ownWrite(unsigned(validation.Middleware(makeTransferEndpoint(ps, as)))),
+			decodeGRPCTransferRequest,
+			encodeGRPCTransactionResponse,
+			opts...,
+		),
+		topUp: kitgrpc.NewServer(
+			write(unsigned(validation.Middleware(makeTopUpEndpoint(ps, as)))),
+			decodeGRPCTopUpRequest,
+			encodeGRPCTopUpResponse,
+			opts...,
+		),
+		withdraw: kitgrpc.NewServer(
+			write(validation.Middleware(makeWithdrawEndpoint(ps, as))),
+			decodeGRPCWithdrawRequest,
+			encodeGRPCTransactionResponse,
+			opts...,
+		),
+		refund: kitgrpc.NewServer(
+			write(validation.Middleware(makeRefundEndpoint(ps))),
+			decodeGRPCRefundRequest,
+			encodeGRPCTransactionResponse,
+			opts...,
+		),
+		errors: problem.NewEncoder(logger),
+	}
+}
+
+func (s *grpcServer) GetBalance(ctx context.Context, req *pb.BalanceRequest) (*pb.BalanceReply, error) {
+	ctx, rep, err := s.getBalance.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, s.errors.GRPCError(ctx, err)
+	}
+	return rep.(*pb.BalanceReply), nil
+}
+
+func (s *grpcServer) ListTransactions(ctx context.Context, req *pb.ListTransactionsRequest) (*pb.ListTransactionsReply, error) {
+	ctx, rep, err := s.listTransactions.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, s.errors.GRPCError(ctx, err)
+	}
+	return rep.(*pb.ListTransactionsReply), nil
+}
+
+func (s *grpcServer) Transfer(ctx context.Context, req *pb.TransferRequest) (*pb.TransactionReply, error) {
+	return s.serveTransaction(ctx, s.transfer, req)
+}
+
+func (s *grpcServer) TopUp(ctx context.Context, req *pb.TopUpRequest) (*pb.TopUpReply, error) {
+	ctx, rep, err := s.topUp.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, s.errors.GRPCError(ctx, err)
+	}
+	return rep.(*pb.TopUpReply), nil
+}
+
+func (s *grpcServer) Withdraw(ctx context.Context, req *pb.WithdrawRequest) (*pb.TransactionReply, error) {
+	return s.serveTransaction(ctx, s.withdraw, req)
+}
+
+func (s *grpcServer) Refund(ctx context.Context, req *pb.RefundRequest) (*pb.TransactionReply, error) {
+	return s.serveTransaction(ctx, s.refund, req)
+}
+
+func (s *grpcServer) serveTransaction(ctx context.Context, h kitgrpc.Handler, req interface{}) (*pb.TransactionReply, error) {
+	ctx, rep, err := h.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, s.errors.GRPCError(ctx, err)
+	}
+	return rep.(*pb.TransactionReply), nil
+}
+
+func decodeGRPCBalanceRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.BalanceRequest)
+	return getBalanceRequest{ID: req.AccountId}, nil
+}
+
+func decodeGRPCListTransactionsRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.ListTransactionsRequest)
+	var (
+		f   TransactionFilter
+		err error
+	)
+	if req.Cursor != "" {
+		if f.After, err = decodeCursor(req.Cursor); err != nil {
+			return nil, errBadRequest{Msg: fmt.Sprintf("cursor is invalid")}
+		}
+	}
+	if req.Limit < 0 {
+		return nil, errBadRequest{Msg: fmt.Sprintf("limit must be positive")}
+	}
+	f.Limit = int(req.Limit)
+	switch o := Order(req.Order); o {
+	case "", OrderAsc, OrderDesc:
+		f.Order = o
+	default:
+		return nil, errBadRequest{Msg: fmt.Sprintf("order must be %s or %s", OrderAsc, OrderDesc)}
+	}
+	return listTransactionsRequest{ID: req.AccountId, Filter: f}, nil
+}
+
+func decodeGRPCTransferRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.TransferRequest)
+	amount, err := parseAmount(req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	currency, err := parseCurrency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	toCurrency, err := parseCurrency(req.ToCurrency)
+	if err != nil {
+		return nil, err
+	}
+	md, err := metadata.Parse(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	key, err := decodeGRPCIdempotencyKey(req.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	return transferRequest{
+		From:           req.From,
+		To:             req.To,
+		Amount:         amount,
+		Currency:       currency,
+		ToCurrency:     toCurrency,
+		Convert:        req.Convert,
+		QuoteID:        req.QuoteId,
+		Reference:      Reference{ExternalID: req.ExternalId, Metadata: md},
+		IdempotencyKey: key,
+	}, nil
+}
+
+func decodeGRPCTopUpRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.TopUpRequest)
+	amount, err := parseAmount(req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	currency, err := parseCurrency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	md, err := metadata.Parse(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	key, err := decodeGRPCIdempotencyKey(req.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	return topUpRequest{
+		AccountID:      req.AccountId,
+		Amount:         amount,
+		Currency:       currency,
+		Reference:      Reference{ExternalID: req.ExternalId, Metadata: md},
+		IdempotencyKey: key,
+	}, nil
+}
+
+func decodeGRPCWithdrawRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.WithdrawRequest)
+	amount, err := parseAmount(req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	currency, err := parseCurrency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	key, err := decodeGRPCIdempotencyKey(req.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	return withdrawRequest{
+		AccountID:      req.AccountId,
+		Amount:         amount,
+		Currency:       currency,
+		IdempotencyKey: key,
+	}, nil
+}
+
+func decodeGRPCRefundRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.RefundRequest)
+	amount, err := parseAmount(req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	key, err := decodeGRPCIdempotencyKey(req.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	return refundRequest{TransactionID: req.TransactionId, Amount: amount, IdempotencyKey: key}, nil
+}
+
+// parseAmount parse optional amount, validation reports missing required ones
+func parseAmount(s string) (money.Amount, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return money.Parse(s)
+}
+
+// parseCurrency parse optional currency code
+func parseCurrency(code string) (money.Currency, error) {
+	if code == "" {
+		return "", nil
+	}
+	return money.ParseCurrency(code)
+}
+
+// decodeGRPCIdempotencyKey check length of optional key, endpoint binds it to the decoded request
+func decodeGRPCIdempotencyKey(key string) (string, error) {
+	if len(key) > maxIdempotencyKeyLength {
+		return "", errBadRequest{Msg: fmt.Sprintf("idempotency_key must be at most %d characters", maxIdempotencyKeyLength)}
+	}
+	return key, nil
+}
+
+func encodeGRPCBalanceResponse(_ context.Context, r interface{}) (interface{}, error) {
+	resp := r.(getBalanceResponse)
+	if resp.Err != nil {
+		return nil, resp.Err
+	}
+	balances := make([]*pb.Balance, 0, len(resp.Balances))
+	for _, b := range resp.Balances {
+		balances = append(balances, balanceToPB(b))
+	}
+	return &pb.BalanceReply{Balances: balances}, nil
+}
+
+func encodeGRPCListTransactionsResponse(_ context.Context, r interface{}) (interface{}, error) {
+	resp := r.(listTransactionsResponse)
+	if resp.Err != nil {
+		return nil, resp.Err
+	}
+	transactions := make([]*pb.Transaction, 0, len(resp.Transactions))
+	for _, t := range resp.Transactions {
+		pt, err := transactionToPB(t)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, pt)
+	}
+	return &pb.ListTransactionsReply{Transactions: transactions, NextCursor: resp.NextCursor}, nil
+}
+
+func encodeGRPCTopUpResponse(_ context.Context, r interface{}) (interface{}, error) {
+	resp := r.(topUpResponse)
+	if resp.Err != nil {
+		return nil, resp.Err
+	}
+	return &pb.TopUpReply{Balance: balanceToPB(resp.Balance)}, nil
+}
+
+// encodeGRPCTransactionResponse encode any response carrying single transaction
+func encodeGRPCTransactionResponse(_ context.Context, r interface{}) (interface{}, error) {
+	if f, ok := r.(response.Failer); ok && f.Failed() != nil {
+		return nil, f.Failed()
+	}
+	var t *Transaction
+	switch resp := r.(type) {
+	case transferResponse:
+		t = resp.Transaction
+	case withdrawResponse:
+		t = resp.Transaction
+	case refundResponse:
+		t = resp.Transaction
+	}
+	pt, err := transactionToPB(t)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.TransactionReply{Transaction: pt}, nil
+}
+
+func balanceToPB(b *Balance) *pb.Balance {
+	if b == nil {
+		return nil
+	}
+	return &pb.Balance{
+		AccountId: b.AccountID,
+		Currency:  b.Currency.String(),
+		Ledger:    b.Ledger.String(),
+		Available: b.Available.String(),
+	}
+}
+
+func transactionToPB(t *Transaction) (*pb.Transaction, error) {
+	if t == nil {
+		return nil, nil
+	}
+	date, err := ptypes.TimestampProto(t.Date)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Transaction{
+		Id:                    t.ID,
+		Kind:                  string(t.Kind),
+		Status:                string(t.Status),
+		From:                  t.From,
+		To:                    t.To,
+		Amount:                t.Amount.String(),
+		Currency:              t.Currency.String(),
+		ToAmount:              t.ToAmount.String(),
+		ToCurrency:            t.ToCurrency.String(),
+		Rate:                  t.Rate.String(),
+		QuoteId:               t.QuoteID,
+		OriginalTransactionId: t.OriginalTransactionID,
+		Internal:              t.Internal,
+		ExternalId:            t.ExternalID,
+		Metadata:              t.Metadata.String(),
+		Date:                  date,
+	}, nil
+}
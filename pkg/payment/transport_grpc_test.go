@@ -0,0 +1,89 @@
+package payment
+
+import (
+	"coins/pb"
+	"coins/pkg/metadata"
+	"coins/pkg/money"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeGRPCTransferRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.TransferRequest
+		want interface{}
+		err  bool
+	}{
+		{
+			"transfer",
+			&pb.TransferRequest{From: 1, To: 2, Amount: "5.00", Currency: "EUR", ExternalId: "inv-1", Metadata: `{"order":"42"}`, IdempotencyKey: "k-1"},
+			transferRequest{From: 1, To: 2, Amount: 500, Currency: "EUR", Reference: Reference{ExternalID: "inv-1", Metadata: metadata.Metadata{"order": "42"}}, IdempotencyKey: "k-1"},
+			false,
+		},
+		{
+			"conversion",
+			&pb.TransferRequest{From: 1, To: 2, Amount: "5.00", Currency: "eur", ToCurrency: "USD", Convert: true, QuoteId: 3},
+			transferRequest{From: 1, To: 2, Amount: 500, Currency: "EUR", ToCurrency: "USD", Convert: true, QuoteID: 3},
+			false,
+		},
+		{"missing amount", &pb.TransferRequest{From: 1, To: 2, Currency: "EUR"}, transferRequest{From: 1, To: 2, Currency: "EUR"}, false},
+		{"bad amount", &pb.TransferRequest{From: 1, To: 2, Amount: "5.001", Currency: "EUR"}, nil, true},
+		{"bad currency", &pb.TransferRequest{From: 1, To: 2, Amount: "5.00", Currency: "XXX"}, nil, true},
+		{"bad to currency", &pb.TransferRequest{From: 1, To: 2, Amount: "5.00", Currency: "EUR", ToCurrency: "XXX"}, nil, true},
+		{"bad metadata", &pb.TransferRequest{From: 1, To: 2, Amount: "5.00", Currency: "EUR", Metadata: `[]`}, nil, true},
+		{"long key", &pb.TransferRequest{From: 1, To: 2, Amount: "5.00", Currency: "EUR", IdempotencyKey: strings.Repeat("k", maxIdempotencyKeyLength+1)}, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := decodeGRPCTransferRequest(context.Background(), tt.req)
+		if (err != nil) != tt.err || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, %v, want %+v", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestDecodeGRPCListTransactionsRequest(t *testing.T) {
+	c := &Cursor{Date: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), ID: 9}
+	tests := []struct {
+		req  *pb.ListTransactionsRequest
+		want interface{}
+		err  bool
+	}{
+		{&pb.ListTransactionsRequest{AccountId: 1}, listTransactionsRequest{ID: 1}, false},
+		{&pb.ListTransactionsRequest{AccountId: 1, Cursor: encodeCursor(c), Limit: 10, Order: "desc"}, listTransactionsRequest{ID: 1, Filter: TransactionFilter{After: c, Limit: 10, Order: OrderDesc}}, false},
+		{&pb.ListTransactionsRequest{AccountId: 1, Cursor: "x"}, nil, true},
+		{&pb.ListTransactionsRequest{AccountId: 1, Limit: -1}, nil, true},
+		{&pb.ListTransactionsRequest{AccountId: 1, Order: "newest"}, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := decodeGRPCListTransactionsRequest(context.Background(), tt.req)
+		if (err != nil) != tt.err || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%+v: got %+v, %v, want %+v", tt.req, got, err, tt.want)
+		}
+	}
+}
+
+func TestEncodeGRPCTransactionResponse(t *testing.T) {
+	tx := newTransaction(KindTransfer, 1, 2, 500, "EUR")
+	tx.ID, tx.ExternalID, tx.Metadata = 3, "inv-1", metadata.Metadata{"order": "42"}
+	got, err := encodeGRPCTransactionResponse(context.Background(), transferResponse{Transaction: tx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt := got.(*pb.TransactionReply).Transaction
+	if pt.Id != 3 || pt.Kind != "transfer" || pt.Status != "completed" || pt.Amount != "5.00" || pt.Currency != "EUR" ||
+		pt.ToAmount != "5.00" || pt.Rate != money.OneRate.String() || pt.ExternalId != "inv-1" || pt.Metadata != `{"order":"42"}` {
+		t.Errorf("got %+v", pt)
+	}
+	if pt.Date.Seconds != tx.Date.Unix() {
+		t.Errorf("got date %v, want %s", pt.Date, tx.Date)
+	}
+
+	want := ErrTransactionNotFound{ID: 3}
+	if _, err := encodeGRPCTransactionResponse(context.Background(), refundResponse{Err: want}); err != want {
+		t.Errorf("failed response: got %v, want %v", err, want)
+	}
+}
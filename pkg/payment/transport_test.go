@@ -0,0 +1,227 @@
+package payment
+
+import (
+	"coins/pkg/account"
+	"coins/pkg/auth"
+	"coins/pkg/metadata"
+	"coins/pkg/money"
+	"coins/pkg/ratelimit"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/gorilla/mux"
+	"golang.org/x/time/rate"
+)
+
+// stubAccounts serve every account as active
+type stubAccounts struct {
+	account.Service
+}
+
+func (stubAccounts) Get(_ context.Context, id int64) (*account.Account, error) {
+	return testAccount(id), nil
+}
+
+// newRequest build request of API key
+func newRequest(method, path, body string) *http.Request {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	r.Header.Set(auth.APIKeyHeader, "token")
+	return r
+}
+
+// serve request of API key by handler
+func serve(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	r := newRequest(method, path, body)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func testHandlers(ps Service, scopes ...auth.Scope) (v1, v2 http.Handler) {
+	authn := stubAuthn{key: &auth.Key{ID: 1, Scopes: scopes}}
+	rl := ratelimit.NewLimiter(rate.Inf, rate.Inf, 1, auth.Identity)
+	return MakeHandlerV1(ps, stubAccounts{}, authn, rl, nil, log.NewNopLogger()),
+		MakeHandler(ps, stubAccounts{}, authn, rl, nil, log.NewNopLogger())
+}
+
+func TestTopUpStatus(t *testing.T) {
+	v1, v2 := testHandlers(NewService(newStubRepo(), stubFX{}), auth.ScopePaymentsWrite)
+	body := `{"account_id":1,"amount":"10.00","currency":"EUR"}`
+	tests := []struct {
+		name    string
+		handler http.Handler
+		path    string
+		status  int
+	}{
+		{"v1", v1, "/payment/v1/topup", http.StatusOK},
+		{"v2", v2, "/v2/payment/topup", http.StatusCreated},
+	}
+	for _, tt := range tests {
+		w := serve(tt.handler, "POST", tt.path, body)
+		if w.Code != tt.status {
+			t.Errorf("%s: got status %d, want %d: %s", tt.name, w.Code, tt.status, w.Body.String())
+		}
+		if !strings.Contains(w.Body.String(), `"ledger":10.00`) {
+			t.Errorf("%s: got body %s", tt.name, w.Body.String())
+		}
+	}
+}
+
+func TestWithdrawalCallbackScope(t *testing.T) {
+	body := `{"status":"completed"}`
+	tests := []struct {
+		name   string
+		scopes []auth.Scope
+		status int
+	}{
+		{"payments:write", []auth.Scope{auth.ScopePaymentsRead, auth.ScopePaymentsWrite}, http.StatusForbidden},
+		{"payouts:write", []auth.Scope{auth.ScopePayoutsWrite}, http.StatusOK},
+		{"admin", []auth.Scope{auth.ScopeAdmin}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		repo := newStubRepo()
+		repo.txs = []*Transaction{{ID: 1, Kind: KindWithdrawal, Status: StatusPending}}
+		v1, v2 := testHandlers(NewService(repo, stubFX{}), tt.scopes...)
+		for path, h := range map[string]http.Handler{"/payment/v1/withdrawals/1/callback": v1, "/v2/payment/withdrawals/1/callback": v2} {
+			if w := serve(h, "POST", path, body); w.Code != tt.status {
+				t.Errorf("%s %s: got status %d, want %d: %s", tt.name, path, w.Code, tt.status, w.Body.String())
+			}
+		}
+		if completed := repo.txs[0].Status == StatusCompleted; completed != (tt.status == http.StatusOK) {
+			t.Errorf("%s: got withdrawal status %s", tt.name, repo.txs[0].Status)
+		}
+	}
+}
+
+func TestDecodeConfirmWithdrawalRequest(t *testing.T) {
+	tests := []struct {
+		id   string
+		body string
+		want interface{}
+		err  bool
+	}{
+		{"1", `{"status":"completed"}`, confirmWithdrawalRequest{ID: 1, Status: StatusCompleted}, false},
+		{"2", `{"status":"failed"}`, confirmWithdrawalRequest{ID: 2, Status: StatusFailed}, false},
+		{"1", `{"status":"pending"}`, nil, true},
+		{"1", `{}`, nil, true},
+		{"1", `{`, nil, true},
+		{"x", `{"status":"completed"}`, nil, true},
+	}
+	for _, tt := range tests {
+		r := mux.SetURLVars(newRequest("POST", "/", tt.body), map[string]string{"id": tt.id})
+		got, err := decodeConfirmWithdrawalRequest(context.Background(), r)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("%s %s: got %+v, %v, want %+v", tt.id, tt.body, got, err, tt.want)
+		}
+	}
+}
+
+func TestDecodeCaptureRequest(t *testing.T) {
+	tests := []struct {
+		body string
+		want interface{}
+		err  bool
+	}{
+		{``, captureRequest{HoldID: 1}, false},
+		{`{}`, captureRequest{HoldID: 1}, false},
+		{`{"amount":"2.50"}`, captureRequest{HoldID: 1, Amount: 250}, false},
+		{`{"amount":"x"}`, nil, true},
+	}
+	for _, tt := range tests {
+		r := mux.SetURLVars(newRequest("POST", "/", tt.body), map[string]string{"id": "1"})
+		got, err := decodeCaptureRequest(context.Background(), r)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("%q: got %+v, %v, want %+v", tt.body, got, err, tt.want)
+		}
+	}
+}
+
+func TestDecodeRefundRequest(t *testing.T) {
+	tests := []struct {
+		body string
+		key  string
+		want interface{}
+		err  bool
+	}{
+		{``, "", refundRequest{TransactionID: 1}, false},
+		{`{"amount":"1.25"}`, "", refundRequest{TransactionID: 1, Amount: 125}, false},
+		{`{"amount":"1.25"}`, "r-1", refundRequest{TransactionID: 1, Amount: 125, IdempotencyKey: "r-1"}, false},
+		{`{"amount":true}`, "", nil, true},
+		{``, strings.Repeat("k", maxIdempotencyKeyLength+1), nil, true},
+	}
+	for _, tt := range tests {
+		r := mux.SetURLVars(newRequest("POST", "/", tt.body), map[string]string{"id": "1"})
+		r.Header.Set("Idempotency-Key", tt.key)
+		got, err := decodeRefundRequest(context.Background(), r)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("%q %q: got %+v, %v, want %+v", tt.body, tt.key, got, err, tt.want)
+		}
+	}
+}
+
+func TestCursor(t *testing.T) {
+	c := &Cursor{Date: time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC), ID: 42}
+	got, err := decodeCursor(encodeCursor(c))
+	if err != nil || *got != *c {
+		t.Fatalf("got %+v, %v, want %+v", got, err, c)
+	}
+	if s := encodeCursor(nil); s != "" {
+		t.Errorf("nil cursor: got %q", s)
+	}
+	for _, s := range []string{"!", "MTIz", "eDox", "MTo"} {
+		if _, err := decodeCursor(s); err == nil {
+			t.Errorf("%q: got no error", s)
+		}
+	}
+}
+
+func TestDecodeListTransactionsRequest(t *testing.T) {
+	since := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	min := money.Amount(150)
+	c := &Cursor{Date: since, ID: 7}
+	tests := []struct {
+		query string
+		want  TransactionFilter
+		err   bool
+	}{
+		{"", TransactionFilter{}, false},
+		{"cursor=" + encodeCursor(c) + "&limit=10&order=desc", TransactionFilter{After: c, Limit: 10, Order: OrderDesc}, false},
+		{"since=2020-01-01T00:00:00Z&direction=incoming&counterparty=3&min_amount=1.50", TransactionFilter{Since: since, Direction: DirectionIncoming, Counterparty: 3, MinAmount: &min}, false},
+		{"cursor=x", TransactionFilter{}, true},
+		{"limit=0", TransactionFilter{}, true},
+		{"order=newest", TransactionFilter{}, true},
+		{"since=yesterday", TransactionFilter{}, true},
+		{"direction=up", TransactionFilter{}, true},
+		{"counterparty=bob", TransactionFilter{}, true},
+		{"max_amount=lots", TransactionFilter{}, true},
+	}
+	for _, tt := range tests {
+		r := mux.SetURLVars(newRequest("GET", "/?"+tt.query, ""), map[string]string{"id": "1"})
+		got, err := decodeListTransactionsRequest(context.Background(), r)
+		if (err != nil) != tt.err {
+			t.Errorf("%q: got error %v", tt.query, err)
+			continue
+		}
+		if want := (listTransactionsRequest{ID: 1, Filter: tt.want}); err == nil && !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: got %+v, want %+v", tt.query, got, want)
+		}
+	}
+}
+
+func TestDecodeTransferReference(t *testing.T) {
+	body := `{"from":1,"to":2,"amount":"5.00","currency":"EUR","external_id":"inv-1","metadata":{"order":"42"}}`
+	got, err := decodeTransferRequest(context.Background(), newRequest("POST", "/", body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := transferRequest{From: 1, To: 2, Amount: 500, Currency: "EUR", Reference: Reference{ExternalID: "inv-1", Metadata: metadata.Metadata{"order": "42"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
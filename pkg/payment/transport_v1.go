@@ -0,0 +1,363 @@
+package payment
+
+import (
+	"coins/pkg/account"
+	"coins/pkg/auth"
+	"coins/pkg/metadata"
+	"coins/pkg/money"
+	"coins/pkg/problem"
+	"coins/pkg/ratelimit"
+	"coins/pkg/response"
+	"coins/pkg/signature"
+	"coins/pkg/validation"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+)
+
+// MakeHandlerV1 build handlers for payment transport of API v1. v1 is frozen: it always answers with legacy
+// statuses(200 for every success) and `{"error": "..."}` bodies and decodes requests with its own decoders
+func MakeHandlerV1(ps Service, as account.Service, authn auth.Authenticator, rl *ratelimit.Limiter, sv *signature.Verifier, logger log.Logger) http.Handler {
+	const prefix = "/payment/v1/"
+	enc := response.NewEncoder(logger, true, http.StatusOK)
+	opts := []kithttp.ServerOption{
+		kithttp.ServerBefore(kithttp.PopulateRequestContext, problem.PopulateRequestID, auth.HTTPToContext),
+		kithttp.ServerAfter(problem.SetRequestIDHeader),
+		kithttp.ServerErrorEncoder(enc.EncodeError),
+	}
+	read, write := rl.Wrap(auth.RequireOwner(authn, auth.ScopePaymentsRead)), rl.Wrap(auth.Require(authn, auth.ScopePaymentsWrite))
+	ownWrite := rl.Wrap(auth.RequireOwner(authn, auth.ScopePaymentsWrite))
+	// only the payout provider reports results of withdrawals
+	payout := rl.Wrap(auth.Require(authn, auth.ScopePayoutsWrite))
+	signedOpts := append([]kithttp.ServerOption{kithttp.ServerBefore(signature.HTTPToContext(sv))}, opts...)
+	signed := signature.Middleware(sv)
+
+	getBalanceHandler := kithttp.NewServer(
+		read(validation.Middleware(makeGetBalanceEndpoint(ps, as))),
+		decodeGetBalanceRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	verifyBalanceHandler := kithttp.NewServer(
+		read(validation.Middleware(makeVerifyBalanceEndpoint(ps, as))),
+		decodeGetBalanceRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	listTransactionsHandler := kithttp.NewServer(
+		read(validation.Middleware(makeListTransactionsEndpoint(ps, as))),
+		decodeListTransactionsRequestV1,
+		enc.OK(),
+		opts...,
+	)
+
+	transferHandler := kithttp.NewServer(
+		ownWrite(signed(validation.Middleware(makeTransferEndpoint(ps, as)))),
+		decodeTransferRequestV1,
+		enc.OK(),
+		signedOpts...,
+	)
+
+	quoteHandler := kithttp.NewServer(
+		ownWrite(validation.Middleware(makeQuoteEndpoint(ps))),
+		decodeQuoteRequestV1,
+		enc.OK(),
+		opts...,
+	)
+
+	topUpHandler := kithttp.NewServer(
+		write(signed(validation.Middleware(makeTopUpEndpoint(ps, as)))),
+		decodeTopUpRequestV1,
+		enc.OK(),
+		signedOpts...,
+	)
+
+	withdrawHandler := kithttp.NewServer(
+		write(validation.Middleware(makeWithdrawEndpoint(ps, as))),
+		decodeWithdrawRequestV1,
+		enc.OK(),
+		opts...,
+	)
+
+	confirmWithdrawalHandler := kithttp.NewServer(
+		payout(validation.Middleware(makeConfirmWithdrawalEndpoint(ps))),
+		decodeConfirmWithdrawalRequestV1,
+		enc.OK(),
+		opts...,
+	)
+
+	authorizeHandler := kithttp.NewServer(
+		write(validation.Middleware(makeAuthorizeEndpoint(ps, as))),
+		decodeAuthorizeRequestV1,
+		enc.OK(),
+		opts...,
+	)
+
+	captureHandler := kithttp.NewServer(
+		write(validation.Middleware(makeCaptureEndpoint(ps))),
+		decodeCaptureRequestV1,
+		enc.OK(),
+		opts...,
+	)
+
+	voidHandler := kithttp.NewServer(
+		write(validation.Middleware(makeVoidEndpoint(ps))),
+		decodeVoidRequest,
+		enc.OK(),
+		opts...,
+	)
+
+	refundHandler := kithttp.NewServer(
+		write(validation.Middleware(makeRefundEndpoint(ps))),
+		decodeRefundRequestV1,
+		enc.OK(),
+		opts...,
+	)
+
+	r := mux.NewRouter()
+
+	r.Handle(prefix+"balance/{id}", getBalanceHandler).Methods("GET")
+	r.Handle(prefix+"balance/{id}/verify", verifyBalanceHandler).Methods("GET")
+	r.Handle(prefix+"transactions/{id}", listTransactionsHandler).Methods("GET")
+	r.Handle(prefix+"transactions/{id}/refund", refundHandler).Methods("POST")
+	r.Handle(prefix+"transfer", signature.LimitBody(transferHandler)).Methods("POST")
+	r.Handle(prefix+"quote", quoteHandler).Methods("POST")
+	r.Handle(prefix+"topup", signature.LimitBody(topUpHandler)).Methods("POST")
+	r.Handle(prefix+"withdraw", withdrawHandler).Methods("POST")
+	r.Handle(prefix+"withdrawals/{id}/callback", confirmWithdrawalHandler).Methods("POST")
+	r.Handle(prefix+"holds", authorizeHandler).Methods("POST")
+	r.Handle(prefix+"holds/{id}/capture", captureHandler).Methods("POST")
+	r.Handle(prefix+"holds/{id}/void", voidHandler).Methods("POST")
+
+	return r
+}
+
+func decodeListTransactionsRequestV1(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := decodeID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var f TransactionFilter
+	q := r.URL.Query()
+	if v := q.Get("cursor"); v != "" {
+		if f.After, err = decodeCursor(v); err != nil {
+			return nil, errBadRequest{Msg: fmt.Sprintf("cursor param is invalid")}
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		if f.Limit, err = strconv.Atoi(v); err != nil || f.Limit <= 0 {
+			return nil, errBadRequest{Msg: fmt.Sprintf("limit param must be positive int")}
+		}
+	}
+	switch o := Order(q.Get("order")); o {
+	case "", OrderAsc, OrderDesc:
+		f.Order = o
+	default:
+		return nil, errBadRequest{Msg: fmt.Sprintf("order param must be %s or %s", OrderAsc, OrderDesc)}
+	}
+	if v := q.Get("since"); v != "" {
+		if f.Since, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, errBadRequest{Msg: fmt.Sprintf("since param must be RFC3339 date")}
+		}
+	}
+	if v := q.Get("until"); v != "" {
+		if f.Until, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, errBadRequest{Msg: fmt.Sprintf("until param must be RFC3339 date")}
+		}
+	}
+	switch d := Direction(q.Get("direction")); d {
+	case "", DirectionIncoming, DirectionOutgoing:
+		f.Direction = d
+	default:
+		return nil, errBadRequest{Msg: fmt.Sprintf("direction param must be %s or %s", DirectionIncoming, DirectionOutgoing)}
+	}
+	if v := q.Get("counterparty"); v != "" {
+		if f.Counterparty, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, errBadRequest{Msg: fmt.Sprintf("counterparty param must be int")}
+		}
+	}
+	if v := q.Get("min_amount"); v != "" {
+		a, err := money.Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		f.MinAmount = &a
+	}
+	if v := q.Get("max_amount"); v != "" {
+		a, err := money.Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		f.MaxAmount = &a
+	}
+	f.ExternalID = q.Get("external_id")
+	f.Metadata = metadata.FilterFromQuery(q)
+	return listTransactionsRequest{ID: id, Filter: f}, nil
+}
+
+func decodeTransferRequestV1(_ context.Context, r *http.Request) (interface{}, error) {
+	var body struct {
+		From       int64             `json:"from"`
+		To         int64             `json:"to"`
+		Amount     money.Amount      `json:"amount"`
+		Currency   money.Currency    `json:"currency"`
+		ToCurrency money.Currency    `json:"to_currency"`
+		Convert    bool              `json:"convert"`
+		QuoteID    int64             `json:"quote_id"`
+		ExternalID string            `json:"external_id"`
+		Metadata   metadata.Metadata `json:"metadata"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	key, err := decodeIdempotencyKey(r)
+	if err != nil {
+		return nil, err
+	}
+	return transferRequest{
+		From:           body.From,
+		To:             body.To,
+		Amount:         body.Amount,
+		Currency:       body.Currency,
+		ToCurrency:     body.ToCurrency,
+		Convert:        body.Convert,
+		QuoteID:        body.QuoteID,
+		Reference:      Reference{ExternalID: body.ExternalID, Metadata: body.Metadata},
+		IdempotencyKey: key,
+	}, nil
+}
+
+func decodeQuoteRequestV1(_ context.Context, r *http.Request) (interface{}, error) {
+	var body struct {
+		Amount     money.Amount   `json:"amount"`
+		Currency   money.Currency `json:"currency"`
+		ToCurrency money.Currency `json:"to_currency"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return quoteRequest{Amount: body.Amount, Currency: body.Currency, ToCurrency: body.ToCurrency}, nil
+}
+
+func decodeTopUpRequestV1(_ context.Context, r *http.Request) (interface{}, error) {
+	var body struct {
+		AccountID  int64             `json:"account_id"`
+		Amount     money.Amount      `json:"amount"`
+		Currency   money.Currency    `json:"currency"`
+		ExternalID string            `json:"external_id"`
+		Metadata   metadata.Metadata `json:"metadata"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	key, err := decodeIdempotencyKey(r)
+	if err != nil {
+		return nil, err
+	}
+	return topUpRequest{
+		AccountID:      body.AccountID,
+		Amount:         body.Amount,
+		Currency:       body.Currency,
+		Reference:      Reference{ExternalID: body.ExternalID, Metadata: body.Metadata},
+		IdempotencyKey: key,
+	}, nil
+}
+
+func decodeWithdrawRequestV1(_ context.Context, r *http.Request) (interface{}, error) {
+	var body struct {
+		AccountID int64          `json:"account_id"`
+		Amount    money.Amount   `json:"amount"`
+		Currency  money.Currency `json:"currency"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	key, err := decodeIdempotencyKey(r)
+	if err != nil {
+		return nil, err
+	}
+	return withdrawRequest{
+		AccountID:      body.AccountID,
+		Amount:         body.Amount,
+		Currency:       body.Currency,
+		IdempotencyKey: key,
+	}, nil
+}
+
+func decodeConfirmWithdrawalRequestV1(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := decodeID(r)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Status Status `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	if body.Status != StatusCompleted && body.Status != StatusFailed {
+		return nil, errBadRequest{Msg: fmt.Sprintf("status param must be %s or %s", StatusCompleted, StatusFailed)}
+	}
+	return confirmWithdrawalRequest{ID: id, Status: body.Status}, nil
+}
+
+func decodeAuthorizeRequestV1(_ context.Context, r *http.Request) (interface{}, error) {
+	var body struct {
+		From     int64          `json:"from"`
+		To       int64          `json:"to"`
+		Amount   money.Amount   `json:"amount"`
+		Currency money.Currency `json:"currency"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return authorizeRequest{From: body.From, To: body.To, Amount: body.Amount, Currency: body.Currency}, nil
+}
+
+func decodeCaptureRequestV1(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := decodeID(r)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Amount money.Amount `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return captureRequest{HoldID: id, Amount: body.Amount}, nil
+}
+
+func decodeRefundRequestV1(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := decodeID(r)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Amount money.Amount `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+		return nil, err
+	}
+	key, err := decodeIdempotencyKey(r)
+	if err != nil {
+		return nil, err
+	}
+	return refundRequest{TransactionID: id, Amount: body.Amount, IdempotencyKey: key}, nil
+}
@@ -0,0 +1,75 @@
+package payment
+
+import "coins/pkg/validation"
+
+// column sizes
+const externalIDMaxLength = 255
+
+func (r transferRequest) Validate() error {
+	var vv validation.Violations
+	vv.ID("from", r.From)
+	vv.ID("to", r.To)
+	vv.DifferentAccounts("to", r.From, r.To)
+	vv.Positive("amount", r.Amount)
+	vv.Currency("currency", r.Currency)
+	if (r.Convert || r.QuoteID != 0) && r.ToCurrency == "" {
+		vv.Add("to_currency", validation.CodeRequired, "is required for conversion")
+	}
+	if r.QuoteID < 0 {
+		vv.Add("quote_id", validation.CodeInvalid, "must be positive ID")
+	}
+	r.Reference.validate(&vv)
+	return vv.Err()
+}
+
+func (r quoteRequest) Validate() error {
+	var vv validation.Violations
+	vv.Positive("amount", r.Amount)
+	vv.Currency("currency", r.Currency)
+	vv.Currency("to_currency", r.ToCurrency)
+	return vv.Err()
+}
+
+func (r topUpRequest) Validate() error {
+	var vv validation.Violations
+	vv.ID("account_id", r.AccountID)
+	vv.Positive("amount", r.Amount)
+	vv.Currency("currency", r.Currency)
+	r.Reference.validate(&vv)
+	return vv.Err()
+}
+
+func (r withdrawRequest) Validate() error {
+	var vv validation.Violations
+	vv.ID("account_id", r.AccountID)
+	vv.Positive("amount", r.Amount)
+	vv.Currency("currency", r.Currency)
+	return vv.Err()
+}
+
+func (r authorizeRequest) Validate() error {
+	var vv validation.Violations
+	vv.ID("from", r.From)
+	vv.ID("to", r.To)
+	vv.DifferentAccounts("to", r.From, r.To)
+	vv.Positive("amount", r.Amount)
+	vv.Currency("currency", r.Currency)
+	return vv.Err()
+}
+
+func (r captureRequest) Validate() error {
+	var vv validation.Violations
+	vv.NotNegative("amount", r.Amount)
+	return vv.Err()
+}
+
+func (r refundRequest) Validate() error {
+	var vv validation.Violations
+	vv.NotNegative("amount", r.Amount)
+	return vv.Err()
+}
+
+func (ref Reference) validate(vv *validation.Violations) {
+	vv.MaxLength("external_id", ref.ExternalID, externalIDMaxLength)
+	vv.Metadata("metadata", ref.Metadata)
+}
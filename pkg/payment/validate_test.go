@@ -0,0 +1,71 @@
+package payment
+
+import (
+	"coins/pkg/metadata"
+	"coins/pkg/validation"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// violations return `field:code` of every violation of err
+func violations(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	verr, ok := err.(validation.Error)
+	if !ok {
+		t.Fatalf("got %T error %v, want validation.Error", err, err)
+	}
+	var vv []string
+	for _, v := range verr.Violations {
+		vv = append(vv, v.Field+":"+v.Code)
+	}
+	return vv
+}
+
+func TestValidate(t *testing.T) {
+	transfer := transferRequest{From: 1, To: 2, Amount: 100, Currency: "EUR"}
+	sameAccount := transfer
+	sameAccount.To = 1
+	convert := transfer
+	convert.Convert = true
+	badQuote := transfer
+	badQuote.QuoteID, badQuote.ToCurrency = -1, "USD"
+	longExternalID := transfer
+	longExternalID.Reference.ExternalID = strings.Repeat("x", externalIDMaxLength+1)
+	badMetadata := transfer
+	badMetadata.Reference.Metadata = metadata.Metadata{"": "x"}
+
+	tests := []struct {
+		name string
+		req  validation.Validator
+		want []string
+	}{
+		{"transfer", transfer, nil},
+		{"empty transfer", transferRequest{}, []string{"from:required", "to:required", "amount:not_positive", "currency:required"}},
+		{"transfer to itself", sameAccount, []string{"to:same_account"}},
+		{"conversion without to_currency", convert, []string{"to_currency:required"}},
+		{"negative quote_id", badQuote, []string{"quote_id:invalid"}},
+		{"long external_id", longExternalID, []string{"external_id:too_long"}},
+		{"empty metadata key", badMetadata, []string{"metadata:invalid"}},
+		{"quote", quoteRequest{Amount: 100, Currency: "EUR", ToCurrency: "USD"}, nil},
+		{"empty quote", quoteRequest{}, []string{"amount:not_positive", "currency:required", "to_currency:required"}},
+		{"topup", topUpRequest{AccountID: 1, Amount: 100, Currency: "EUR"}, nil},
+		{"negative topup", topUpRequest{AccountID: 1, Amount: -100, Currency: "EUR"}, []string{"amount:not_positive"}},
+		{"withdraw", withdrawRequest{AccountID: 1, Amount: 100, Currency: "EUR"}, nil},
+		{"withdraw without account", withdrawRequest{Amount: 100, Currency: "EUR"}, []string{"account_id:required"}},
+		{"authorize", authorizeRequest{From: 1, To: 2, Amount: 100, Currency: "EUR"}, nil},
+		{"authorize to itself", authorizeRequest{From: 1, To: 1, Amount: 100, Currency: "EUR"}, []string{"to:same_account"}},
+		{"full capture", captureRequest{HoldID: 1}, nil},
+		{"negative capture", captureRequest{HoldID: 1, Amount: -1}, []string{"amount:negative"}},
+		{"full refund", refundRequest{TransactionID: 1}, nil},
+		{"negative refund", refundRequest{TransactionID: 1, Amount: -1}, []string{"amount:negative"}},
+	}
+	for _, tt := range tests {
+		if got := violations(t, tt.req.Validate()); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
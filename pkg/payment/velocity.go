@@ -0,0 +1,91 @@
+package payment
+
+import (
+	"coins/pkg/account"
+	"coins/pkg/money"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// Limit name of velocity limit
+type Limit string
+
+// velocity limits
+const (
+	LimitTransfersPerMinute Limit = "transfers_per_minute"
+	LimitDailyOutflow       Limit = "daily_outflow"
+	// spending limits of account wallet
+	LimitMaxTransaction    Limit = "max_transaction"
+	LimitDailyOutflowCap   Limit = "daily_outflow_cap"
+	LimitMonthlyOutflowCap Limit = "monthly_outflow_cap"
+)
+
+// VelocityLimit of account tier. TransfersPerMinute caps outgoing transfers of account within last minute,
+// DailyOutflow caps amount of each currency leaving account by transfers, captures and withdrawals within last 24 hours,
+// active holds count towards it, they're checked when authorized and their captures aren't checked again. Zero TransfersPerMinute and currencies missing in DailyOutflow are unlimited
+type VelocityLimit struct {
+	TransfersPerMinute int                             `json:"transfers_per_minute"`
+	DailyOutflow       map[money.Currency]money.Amount `json:"daily_outflow"`
+}
+
+// DefaultVelocityLimits limits of tiers used when service is built without WithVelocityLimits
+var DefaultVelocityLimits = map[account.Tier]VelocityLimit{
+	account.TierStandard: {TransfersPerMinute: 30},
+	account.TierPremium:  {TransfersPerMinute: 120},
+}
+
+// WithVelocityLimits - set velocity limits of account tiers, accounts of tiers without limits are unlimited
+func WithVelocityLimits(limits map[account.Tier]VelocityLimit) Option {
+	return func(s *service) {
+		s.limits = limits
+	}
+}
+
+// LoadVelocityLimits read JSON object of limits by tier from file, e.g.
+// {"standard": {"transfers_per_minute": 10, "daily_outflow": {"USD": "1000.00"}}}
+func LoadVelocityLimits(file string) (map[account.Tier]VelocityLimit, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read velocity limits")
+	}
+	var limits map[account.Tier]VelocityLimit
+	if err := json.Unmarshal(b, &limits); err != nil {
+		return nil, errors.Wrap(err, "unable to parse velocity limits")
+	}
+	for tier, l := range limits {
+		if !tier.Valid() {
+			return nil, errors.Errorf("unknown account tier %q", tier)
+		}
+		for c := range l.DailyOutflow {
+			if _, err := money.ParseCurrency(string(c)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return limits, nil
+}
+
+type velocityLimitCtx struct{}
+
+// WithVelocityLimit return context carrying velocity limit of the tier of account money leaves, the repository
+// checks it after the account is locked
+func WithVelocityLimit(ctx context.Context, l VelocityLimit) context.Context {
+	return context.WithValue(ctx, velocityLimitCtx{}, l)
+}
+
+// VelocityLimitFromContext return velocity limit of the request, account of tier without limits is unlimited
+func VelocityLimitFromContext(ctx context.Context) (VelocityLimit, bool) {
+	l, ok := ctx.Value(velocityLimitCtx{}).(VelocityLimit)
+	return l, ok
+}
+
+// withVelocityLimit return context carrying velocity limit of account tier
+func (s *service) withVelocityLimit(ctx context.Context, a *account.Account) context.Context {
+	if l, ok := s.limits[a.Tier]; ok {
+		return WithVelocityLimit(ctx, l)
+	}
+	return ctx
+}
@@ -0,0 +1,97 @@
+package problem
+
+import "net/http"
+
+// stable error codes returned to clients, codes never change once published
+const (
+	CodeMalformedRequest        = "MALFORMED_REQUEST"
+	CodeInvalidParameter        = "INVALID_PARAMETER"
+	CodeValidationFailed        = "VALIDATION_FAILED"
+	CodeInvalidAmount           = "INVALID_AMOUNT"
+	CodeAmountTooPrecise        = "AMOUNT_TOO_PRECISE"
+	CodeAmountOverflow          = "AMOUNT_OVERFLOW"
+	CodeUnsupportedCurrency     = "UNSUPPORTED_CURRENCY"
+	CodeInvalidRate             = "INVALID_RATE"
+	CodeAccountNotFound         = "ACCOUNT_NOT_FOUND"
+	CodeCustomerNotFound        = "CUSTOMER_NOT_FOUND"
+	CodeTransactionNotFound     = "TRANSACTION_NOT_FOUND"
+	CodeQuoteNotFound           = "QUOTE_NOT_FOUND"
+	CodeHoldNotFound            = "HOLD_NOT_FOUND"
+	CodeInsufficientFunds       = "INSUFFICIENT_FUNDS"
+	CodeCurrencyMismatch        = "CURRENCY_MISMATCH"
+	CodeQuoteMismatch           = "QUOTE_MISMATCH"
+	CodeCaptureExceedsHold      = "CAPTURE_EXCEEDS_HOLD"
+	CodeNotRefundable           = "NOT_REFUNDABLE"
+	CodeRefundExceedsOriginal   = "REFUND_EXCEEDS_ORIGINAL"
+	CodeAccountFrozen           = "ACCOUNT_FROZEN"
+	CodeAccountClosed           = "ACCOUNT_CLOSED"
+	CodeInvalidStatusTransition = "INVALID_STATUS_TRANSITION"
+	CodeNonZeroBalance          = "NON_ZERO_BALANCE"
+	CodeDuplicateExternalID     = "DUPLICATE_EXTERNAL_ID"
+	CodeIdempotencyConflict     = "IDEMPOTENCY_CONFLICT"
+	CodeWithdrawalNotPending    = "WITHDRAWAL_NOT_PENDING"
+	CodeHoldNotActive           = "HOLD_NOT_ACTIVE"
+	CodeQuoteExpired            = "QUOTE_EXPIRED"
+	CodeHoldExpired             = "HOLD_EXPIRED"
+	CodeRateUnavailable         = "RATE_UNAVAILABLE"
+	CodeAccountBusy             = "ACCOUNT_BUSY"
+	CodeUnauthenticated         = "UNAUTHENTICATED"
+	CodeForbidden               = "FORBIDDEN"
+	CodeAPIKeyNotFound          = "API_KEY_NOT_FOUND"
+	CodeInvalidSignature        = "INVALID_SIGNATURE"
+	CodeRequestReplayed         = "REQUEST_REPLAYED"
+	CodeLimitExceeded           = "LIMIT_EXCEEDED"
+	CodeRateLimited             = "RATE_LIMITED"
+	CodeInternal                = "INTERNAL"
+)
+
+// statuses HTTP status of every code, unknown codes are internal errors
+var statuses = map[string]int{
+	CodeMalformedRequest:        http.StatusBadRequest,
+	CodeInvalidParameter:        http.StatusBadRequest,
+	CodeValidationFailed:        http.StatusUnprocessableEntity,
+	CodeInvalidAmount:           http.StatusBadRequest,
+	CodeAmountTooPrecise:        http.StatusBadRequest,
+	CodeAmountOverflow:          http.StatusBadRequest,
+	CodeUnsupportedCurrency:     http.StatusBadRequest,
+	CodeInvalidRate:             http.StatusBadRequest,
+	CodeAccountNotFound:         http.StatusNotFound,
+	CodeCustomerNotFound:        http.StatusNotFound,
+	CodeTransactionNotFound:     http.StatusNotFound,
+	CodeQuoteNotFound:           http.StatusNotFound,
+	CodeHoldNotFound:            http.StatusNotFound,
+	CodeInsufficientFunds:       http.StatusBadRequest,
+	CodeCurrencyMismatch:        http.StatusBadRequest,
+	CodeQuoteMismatch:           http.StatusBadRequest,
+	CodeCaptureExceedsHold:      http.StatusBadRequest,
+	CodeNotRefundable:           http.StatusBadRequest,
+	CodeRefundExceedsOriginal:   http.StatusBadRequest,
+	CodeAccountFrozen:           http.StatusConflict,
+	CodeAccountClosed:           http.StatusConflict,
+	CodeInvalidStatusTransition: http.StatusConflict,
+	CodeNonZeroBalance:          http.StatusConflict,
+	CodeDuplicateExternalID:     http.StatusConflict,
+	CodeIdempotencyConflict:     http.StatusConflict,
+	CodeWithdrawalNotPending:    http.StatusConflict,
+	CodeHoldNotActive:           http.StatusConflict,
+	CodeQuoteExpired:            http.StatusGone,
+	CodeHoldExpired:             http.StatusGone,
+	CodeRateUnavailable:         http.StatusUnprocessableEntity,
+	CodeAccountBusy:             http.StatusServiceUnavailable,
+	CodeUnauthenticated:         http.StatusUnauthorized,
+	CodeForbidden:               http.StatusForbidden,
+	CodeAPIKeyNotFound:          http.StatusNotFound,
+	CodeInvalidSignature:        http.StatusUnauthorized,
+	CodeRequestReplayed:         http.StatusConflict,
+	CodeLimitExceeded:           http.StatusUnprocessableEntity,
+	CodeRateLimited:             http.StatusTooManyRequests,
+	CodeInternal:                http.StatusInternalServerError,
+}
+
+// Status return HTTP status of code
+func Status(code string) int {
+	if s, ok := statuses[code]; ok {
+		return s
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,78 @@
+package problem
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// metadata keys of gRPC requests and responses
+const (
+	RequestIDMetadata = "x-request-id"
+	CodeMetadata      = "x-error-code"
+)
+
+// grpcCodes gRPC code of every HTTP status used by codes
+var grpcCodes = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusConflict:            codes.FailedPrecondition,
+	http.StatusGone:                codes.FailedPrecondition,
+	http.StatusUnprocessableEntity: codes.InvalidArgument,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusInternalServerError: codes.Internal,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+}
+
+// GRPCCode return gRPC code matching HTTP status of problem
+func (p Problem) GRPCCode() codes.Code {
+	switch p.Code {
+	case CodeDuplicateExternalID:
+		return codes.AlreadyExists
+	case CodeIdempotencyConflict:
+		return codes.Aborted
+	case CodeLimitExceeded:
+		return codes.ResourceExhausted
+	}
+	if c, ok := grpcCodes[p.Status]; ok {
+		return c
+	}
+	return codes.Unknown
+}
+
+// PopulateGRPCRequestID implements kitgrpc.ServerRequestFunc, put request ID from metadata or a new one into context
+func PopulateGRPCRequestID(ctx context.Context, md metadata.MD) context.Context {
+	var id string
+	if v := md.Get(RequestIDMetadata); len(v) > 0 {
+		id = v[0]
+	}
+	if id == "" || len(id) > maxRequestIDLength {
+		id = newRequestID()
+	}
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// SetGRPCRequestID implements kitgrpc.ServerResponseFunc, return request ID to client in header
+func SetGRPCRequestID(ctx context.Context, header *metadata.MD, _ *metadata.MD) context.Context {
+	if id := RequestIDFromContext(ctx); id != "" {
+		*header = metadata.Join(*header, metadata.Pairs(RequestIDMetadata, id))
+	}
+	return ctx
+}
+
+// GRPCError return gRPC status error of err, stable code and request ID are sent to client in trailer
+func (e *Encoder) GRPCError(ctx context.Context, err error) error {
+	p := e.Resolve(ctx, err)
+	md := metadata.Pairs(CodeMetadata, p.Code)
+	if p.RequestID != "" {
+		md.Set(RequestIDMetadata, p.RequestID)
+	}
+	grpc.SetTrailer(ctx, md)
+	return status.Error(p.GRPCCode(), p.Detail)
+}
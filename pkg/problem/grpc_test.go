@@ -0,0 +1,71 @@
+package problem
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type codeError string
+
+func (e codeError) Error() string {
+	return "failed with " + string(e)
+}
+
+func (e codeError) Code() string {
+	return string(e)
+}
+
+func TestGRPCCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want codes.Code
+	}{
+		{CodeValidationFailed, codes.InvalidArgument},
+		{CodeMalformedRequest, codes.InvalidArgument},
+		{CodeAccountNotFound, codes.NotFound},
+		{CodeAccountFrozen, codes.FailedPrecondition},
+		{CodeHoldExpired, codes.FailedPrecondition},
+		{CodeDuplicateExternalID, codes.AlreadyExists},
+		{CodeIdempotencyConflict, codes.Aborted},
+		{CodeLimitExceeded, codes.ResourceExhausted},
+		{CodeRateLimited, codes.ResourceExhausted},
+		{CodeUnauthenticated, codes.Unauthenticated},
+		{CodeForbidden, codes.PermissionDenied},
+		{CodeAccountBusy, codes.Unavailable},
+		{CodeInternal, codes.Internal},
+		{"UNKNOWN_CODE", codes.Internal},
+	}
+	for _, tt := range tests {
+		if got := FromError(context.Background(), codeError(tt.code)).GRPCCode(); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestGRPCError(t *testing.T) {
+	err := NewEncoder(log.NewNopLogger()).GRPCError(context.Background(), codeError(CodeAccountNotFound))
+	s, ok := status.FromError(err)
+	if !ok || s.Code() != codes.NotFound || s.Message() != "failed with ACCOUNT_NOT_FOUND" {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestGRPCRequestID(t *testing.T) {
+	ctx := PopulateGRPCRequestID(context.Background(), metadata.Pairs(RequestIDMetadata, "req-1"))
+	if id := RequestIDFromContext(ctx); id != "req-1" {
+		t.Errorf("got request ID %q, want req-1", id)
+	}
+	if id := RequestIDFromContext(PopulateGRPCRequestID(context.Background(), metadata.MD{})); id == "" {
+		t.Error("got no request ID for request without one")
+	}
+	var header metadata.MD
+	SetGRPCRequestID(ctx, &header, nil)
+	if v := header.Get(RequestIDMetadata); len(v) != 1 || v[0] != "req-1" {
+		t.Errorf("got header %v", header)
+	}
+}
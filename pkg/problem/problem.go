@@ -0,0 +1,128 @@
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/go-kit/kit/log"
+)
+
+// ContentType of RFC 7807 problem document
+const ContentType = "application/problem+json"
+
+// retryAfter seconds to wait before retry of temporary error
+const retryAfter = "1"
+
+// Coder implemented by errors safe to show to clients, Code is one of stable error codes
+type Coder interface {
+	Code() string
+}
+
+// Extender implemented by errors adding members to problem document, like list of invalid fields
+type Extender interface {
+	Extensions() map[string]interface{}
+}
+
+// Problem RFC 7807 problem details with stable error code and ID of the failed request
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Code       string
+	RequestID  string
+	Extensions map[string]interface{}
+}
+
+// MarshalJSON encode problem members and extensions as one object
+func (p Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.Extensions)+7)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+	m["type"] = p.Type
+	m["title"] = p.Title
+	m["status"] = p.Status
+	m["code"] = p.Code
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+	if p.RequestID != "" {
+		m["request_id"] = p.RequestID
+	}
+	return json.Marshal(m)
+}
+
+// SetHeaders set request ID and, for temporary errors, Retry-After headers of response
+func (p Problem) SetHeaders(w http.ResponseWriter) {
+	if p.RequestID != "" {
+		w.Header().Set(RequestIDHeader, p.RequestID)
+	}
+	if p.Code == CodeAccountBusy || p.Code == CodeRateLimited {
+		w.Header().Set("Retry-After", retryAfter)
+	}
+}
+
+// FromError build problem of error. Errors without code are internal, their details are replaced by generic
+// message, so database errors and alike never reach clients
+func FromError(ctx context.Context, err error) Problem {
+	p := Problem{
+		Type:      "about:blank",
+		Code:      CodeInternal,
+		Detail:    "internal error, report request ID to support",
+		RequestID: RequestIDFromContext(ctx),
+	}
+	switch e := err.(type) {
+	case Coder:
+		p.Code = e.Code()
+		p.Detail = err.Error()
+	case *json.SyntaxError, *json.UnmarshalTypeError:
+		p.Code = CodeMalformedRequest
+		p.Detail = err.Error()
+	default:
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			p.Code = CodeMalformedRequest
+			p.Detail = "request body is empty or truncated"
+		}
+	}
+	if e, ok := err.(Extender); ok {
+		p.Extensions = e.Extensions()
+	}
+	p.Status = Status(p.Code)
+	p.Title = http.StatusText(p.Status)
+	return p
+}
+
+// Encoder write errors as problem documents and log internal ones
+type Encoder struct {
+	logger log.Logger
+}
+
+// NewEncoder build Encoder logging details of internal errors to logger
+func NewEncoder(logger log.Logger) *Encoder {
+	return &Encoder{logger: logger}
+}
+
+// Resolve build problem of error and log details of internal error
+func (e *Encoder) Resolve(ctx context.Context, err error) Problem {
+	p := FromError(ctx, err)
+	if p.Code == CodeInternal {
+		e.logger.Log("request_id", p.RequestID, "err", err)
+	}
+	return p
+}
+
+// EncodeError implements kithttp.ErrorEncoder
+func (e *Encoder) EncodeError(ctx context.Context, err error, w http.ResponseWriter) {
+	p := e.Resolve(ctx, err)
+	p.SetHeaders(w)
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
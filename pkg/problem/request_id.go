@@ -0,0 +1,47 @@
+package problem
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// RequestIDHeader header carrying request ID, provided by client or generated
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength longer client request IDs are replaced by generated ones
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// PopulateRequestID implements kithttp.RequestFunc, put request ID from header or a new one into context
+func PopulateRequestID(ctx context.Context, r *http.Request) context.Context {
+	id := r.Header.Get(RequestIDHeader)
+	if id == "" || len(id) > maxRequestIDLength {
+		id = newRequestID()
+	}
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// SetRequestIDHeader implements kithttp.ServerResponseFunc, return request ID to client
+func SetRequestIDHeader(ctx context.Context, w http.ResponseWriter) context.Context {
+	if id := RequestIDFromContext(ctx); id != "" {
+		w.Header().Set(RequestIDHeader, id)
+	}
+	return ctx
+}
+
+// RequestIDFromContext return request ID or empty string
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
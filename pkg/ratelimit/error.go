@@ -0,0 +1,17 @@
+package ratelimit
+
+import (
+	"coins/pkg/problem"
+)
+
+// ErrLimited - raised when client exceeds request rate
+type ErrLimited struct{}
+
+func (e ErrLimited) Error() string {
+	return "request rate limit exceeded, retry later"
+}
+
+// Code implements problem.Coder
+func (e ErrLimited) Code() string {
+	return problem.CodeRateLimited
+}
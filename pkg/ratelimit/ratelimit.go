@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	kitratelimit "github.com/go-kit/kit/ratelimit"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc/peer"
+)
+
+// idleTTL how long bucket of client is kept after its last request
+const idleTTL = 10 * time.Minute
+
+// Identifier return identity of authenticated client, like API key ID, false for anonymous requests
+type Identifier func(context.Context) (string, bool)
+
+// Limiter limit request rate of every client with token buckets, requests are limited by client IP before
+// authentication and by identity of the client after
+type Limiter struct {
+	ip       *buckets
+	client   *buckets
+	identify Identifier
+}
+
+// NewLimiter build limiter allowing ipRate requests per second from every IP and clientRate requests per second
+// of every client identified by identify, with bursts up to burst requests
+func NewLimiter(ipRate, clientRate rate.Limit, burst int, identify Identifier) *Limiter {
+	return &Limiter{
+		ip:       newBuckets(ipRate, burst),
+		client:   newBuckets(clientRate, burst),
+		identify: identify,
+	}
+}
+
+// Wrap return authentication middleware limited by IP before and by client identity after authentication.
+// Nil limiter doesn't limit requests
+func (l *Limiter) Wrap(authenticate endpoint.Middleware) endpoint.Middleware {
+	if l == nil {
+		return authenticate
+	}
+	return endpoint.Chain(l.limit(l.ip, clientIP), authenticate, l.limit(l.client, l.identify))
+}
+
+func (l *Limiter) limit(b *buckets, key Identifier) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			k, ok := key(ctx)
+			if !ok {
+				return next(ctx, request)
+			}
+			resp, err := kitratelimit.NewErroringLimiter(b.get(k))(next)(ctx, request)
+			if err == kitratelimit.ErrLimited {
+				return nil, ErrLimited{}
+			}
+			return resp, err
+		}
+	}
+}
+
+// clientIP return remote IP of HTTP(populated by kithttp.PopulateRequestContext) or gRPC request
+func clientIP(ctx context.Context) (string, bool) {
+	addr, _ := ctx.Value(kithttp.ContextKeyRequestRemoteAddr).(string)
+	if addr == "" {
+		p, ok := peer.FromContext(ctx)
+		if !ok {
+			return "", false
+		}
+		addr = p.Addr.String()
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host, true
+	}
+	return addr, true
+}
+
+// buckets token bucket of every key, buckets of idle keys are dropped
+type buckets struct {
+	mu        sync.Mutex
+	rate      rate.Limit
+	burst     int
+	limiters  map[string]*bucket
+	nextSweep time.Time
+}
+
+type bucket struct {
+	*rate.Limiter
+	seen time.Time
+}
+
+func newBuckets(r rate.Limit, burst int) *buckets {
+	return &buckets{rate: r, burst: burst, limiters: make(map[string]*bucket)}
+}
+
+func (b *buckets) get(key string) *rate.Limiter {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	if now.After(b.nextSweep) {
+		for k, l := range b.limiters {
+			if now.Sub(l.seen) > idleTTL {
+				delete(b.limiters, k)
+			}
+		}
+		b.nextSweep = now.Add(idleTTL)
+	}
+	l, ok := b.limiters[key]
+	if !ok {
+		l = &bucket{Limiter: rate.NewLimiter(b.rate, b.burst)}
+		b.limiters[key] = l
+	}
+	l.seen = now
+	return l.Limiter
+}
@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"golang.org/x/time/rate"
+)
+
+type identityKey struct{}
+
+func identity(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(identityKey{}).(string)
+	return id, ok
+}
+
+func nop(ctx context.Context, _ interface{}) (interface{}, error) {
+	return nil, nil
+}
+
+// authenticate put identity into context like auth middleware does
+func authenticate(id string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			return next(context.WithValue(ctx, identityKey{}, id), request)
+		}
+	}
+}
+
+func fromIP(ip string) context.Context {
+	return context.WithValue(context.Background(), kithttp.ContextKeyRequestRemoteAddr, ip+":1234")
+}
+
+func TestClientLimitExhaustion(t *testing.T) {
+	l := NewLimiter(rate.Inf, rate.Every(1e12), 3, identity)
+	alice, bob := l.Wrap(authenticate("alice"))(nop), l.Wrap(authenticate("bob"))(nop)
+	for i := 0; i < 3; i++ {
+		if _, err := alice(fromIP("10.0.0.1"), nil); err != nil {
+			t.Fatalf("request %d within burst: %v", i, err)
+		}
+	}
+	if _, err := alice(fromIP("10.0.0.2"), nil); err != (ErrLimited{}) {
+		t.Fatalf("request over burst from another IP: got %v, want ErrLimited", err)
+	}
+	if _, err := bob(fromIP("10.0.0.1"), nil); err != nil {
+		t.Fatalf("other client is limited: %v", err)
+	}
+}
+
+func TestIPLimitExhaustion(t *testing.T) {
+	l := NewLimiter(rate.Every(1e12), rate.Inf, 2, identity)
+	for i, client := range []string{"alice", "bob"} {
+		if _, err := l.Wrap(authenticate(client))(nop)(fromIP("10.0.0.1"), nil); err != nil {
+			t.Fatalf("request %d within burst: %v", i, err)
+		}
+	}
+	if _, err := l.Wrap(authenticate("carol"))(nop)(fromIP("10.0.0.1"), nil); err != (ErrLimited{}) {
+		t.Fatalf("request over IP burst: got %v, want ErrLimited", err)
+	}
+	if _, err := l.Wrap(authenticate("carol"))(nop)(fromIP("10.0.0.2"), nil); err != nil {
+		t.Fatalf("other IP is limited: %v", err)
+	}
+}
+
+func TestNilLimiter(t *testing.T) {
+	var l *Limiter
+	for i := 0; i < 100; i++ {
+		if _, err := l.Wrap(authenticate("alice"))(nop)(fromIP("10.0.0.1"), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package response
+
+import (
+	"coins/pkg/problem"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-kit/kit/log"
+	kithttp "github.com/go-kit/kit/transport/http"
+)
+
+const contentType = "application/json; charset=utf-8"
+
+// Failer implemented by responses carrying business-logic error
+type Failer interface {
+	Failed() error
+}
+
+// Locator implemented by responses of created resources, Location is the path of the resource relative to its
+// collection or empty when the resource can't be retrieved
+type Locator interface {
+	Location() string
+}
+
+// Encoder write responses and errors of all transports. In compatibility mode it reproduces responses
+// of the first API version: every success has the same legacy status and errors are `{"error": "<message>"}`
+type Encoder struct {
+	problems     *problem.Encoder
+	compat       bool
+	legacyStatus int
+}
+
+// NewEncoder build Encoder, legacyStatus is the status of every success response in compatibility mode
+func NewEncoder(logger log.Logger, compat bool, legacyStatus int) *Encoder {
+	return &Encoder{problems: problem.NewEncoder(logger), compat: compat, legacyStatus: legacyStatus}
+}
+
+// OK return response encoder writing 200
+func (e *Encoder) OK() kithttp.EncodeResponseFunc {
+	return e.encode(http.StatusOK, "")
+}
+
+// Created return response encoder writing 201 for resources which can't be retrieved by location
+func (e *Encoder) Created() kithttp.EncodeResponseFunc {
+	return e.encode(http.StatusCreated, "")
+}
+
+// CreatedIn return response encoder writing 201 and Location header of resource created in collection,
+// e.g. "/account/v1/"
+func (e *Encoder) CreatedIn(collection string) kithttp.EncodeResponseFunc {
+	return e.encode(http.StatusCreated, collection)
+}
+
+func (e *Encoder) encode(status int, collection string) kithttp.EncodeResponseFunc {
+	return func(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+		if f, ok := response.(Failer); ok && f.Failed() != nil {
+			return f.Failed()
+		}
+		code := status
+		if e.compat {
+			code = e.legacyStatus
+		}
+		if l, ok := response.(Locator); ok && collection != "" && code == http.StatusCreated {
+			if loc := l.Location(); loc != "" {
+				w.Header().Set("Location", collection+loc)
+			}
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(code)
+		return json.NewEncoder(w).Encode(response)
+	}
+}
+
+// EncodeError implements kithttp.ErrorEncoder, write problem document or legacy error in compatibility mode
+func (e *Encoder) EncodeError(ctx context.Context, err error, w http.ResponseWriter) {
+	if !e.compat {
+		e.problems.EncodeError(ctx, err, w)
+		return
+	}
+	p := e.problems.Resolve(ctx, err)
+	p.SetHeaders(w)
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": p.Detail,
+	})
+}
@@ -0,0 +1,106 @@
+package response
+
+import (
+	"coins/pkg/problem"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	kithttp "github.com/go-kit/kit/transport/http"
+)
+
+type created struct {
+	ID int64 `json:"id"`
+}
+
+func (c created) Location() string {
+	return "7"
+}
+
+type failed struct{}
+
+func (failed) Failed() error {
+	return errNotFound{}
+}
+
+type errNotFound struct{}
+
+func (errNotFound) Error() string {
+	return "account with ID 7 not found"
+}
+
+func (errNotFound) Code() string {
+	return problem.CodeAccountNotFound
+}
+
+func TestEncode(t *testing.T) {
+	modern := NewEncoder(log.NewNopLogger(), false, 0)
+	legacy := NewEncoder(log.NewNopLogger(), true, http.StatusOK)
+	tests := []struct {
+		name     string
+		encode   kithttp.EncodeResponseFunc
+		response interface{}
+		status   int
+		location string
+		err      error
+	}{
+		{"ok", modern.OK(), created{ID: 7}, http.StatusOK, "", nil},
+		{"created", modern.Created(), created{ID: 7}, http.StatusCreated, "", nil},
+		{"created in", modern.CreatedIn("/v2/account/"), created{ID: 7}, http.StatusCreated, "/v2/account/7", nil},
+		{"created in without location", modern.CreatedIn("/v2/account/"), struct{}{}, http.StatusCreated, "", nil},
+		{"legacy created in", legacy.CreatedIn("/account/v1/"), created{ID: 7}, http.StatusOK, "", nil},
+		{"legacy created", NewEncoder(log.NewNopLogger(), true, http.StatusCreated).CreatedIn("/account/v1/"), created{ID: 7}, http.StatusCreated, "/account/v1/7", nil},
+		{"failed", modern.OK(), failed{}, 0, "", errNotFound{}},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		err := tt.encode(context.Background(), w, tt.response)
+		if err != tt.err {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, tt.err)
+		}
+		if err != nil {
+			continue
+		}
+		if w.Code != tt.status {
+			t.Errorf("%s: got status %d, want %d", tt.name, w.Code, tt.status)
+		}
+		if loc := w.Header().Get("Location"); loc != tt.location {
+			t.Errorf("%s: got location %q, want %q", tt.name, loc, tt.location)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != contentType {
+			t.Errorf("%s: got content type %q", tt.name, ct)
+		}
+	}
+}
+
+func TestEncodeError(t *testing.T) {
+	tests := []struct {
+		name        string
+		compat      bool
+		err         error
+		status      int
+		contentType string
+		body        string
+	}{
+		{"problem", false, errNotFound{}, http.StatusNotFound, problem.ContentType, `"code":"ACCOUNT_NOT_FOUND"`},
+		{"legacy", true, errNotFound{}, http.StatusNotFound, contentType, `{"error":"account with ID 7 not found"}`},
+		{"legacy internal", true, errors.New("connection refused"), http.StatusInternalServerError, contentType, `{"error":"internal error, report request ID to support"}`},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		NewEncoder(log.NewNopLogger(), tt.compat, http.StatusOK).EncodeError(context.Background(), tt.err, w)
+		if w.Code != tt.status {
+			t.Errorf("%s: got status %d, want %d", tt.name, w.Code, tt.status)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != tt.contentType {
+			t.Errorf("%s: got content type %q, want %q", tt.name, ct, tt.contentType)
+		}
+		if !strings.Contains(w.Body.String(), tt.body) {
+			t.Errorf("%s: got body %s, want it to contain %s", tt.name, w.Body.String(), tt.body)
+		}
+	}
+}
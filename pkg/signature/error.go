@@ -0,0 +1,34 @@
+package signature
+
+import (
+	"coins/pkg/problem"
+)
+
+// ErrInvalidSignature - raised when signed request lacks signature headers, is signed by unknown partner or
+// with unknown or expired secret, or its timestamp is out of allowed skew
+type ErrInvalidSignature struct {
+	Reason string
+}
+
+func (e ErrInvalidSignature) Error() string {
+	return "invalid request signature: " + e.Reason
+}
+
+// Code implements problem.Coder
+func (e ErrInvalidSignature) Code() string {
+	return problem.CodeInvalidSignature
+}
+
+// ErrReplayed - raised when nonce of signed request was already used by the partner
+type ErrReplayed struct {
+	Nonce string
+}
+
+func (e ErrReplayed) Error() string {
+	return "request with nonce " + e.Nonce + " was already served"
+}
+
+// Code implements problem.Coder
+func (e ErrReplayed) Code() string {
+	return problem.CodeRequestReplayed
+}
@@ -0,0 +1,84 @@
+package signature
+
+import (
+	"coins/pkg/auth"
+	"context"
+	"net/http"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type requestKey struct{}
+
+type partnerKey struct{}
+
+type read struct {
+	req Request
+	err error
+}
+
+// LimitBody return handler limiting body of request to MaxBodySize, so HTTPToContext doesn't buffer
+// arbitrary large bodies
+func LimitBody(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, MaxBodySize)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// HTTPToContext return kithttp.RequestFunc reading signature headers and body of request into context, the
+// signature is verified by Middleware after the client is authenticated. Nil verifier disables signing
+func HTTPToContext(v *Verifier) func(context.Context, *http.Request) context.Context {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		if v == nil {
+			return ctx
+		}
+		req, err := ReadRequest(r)
+		return context.WithValue(ctx, requestKey{}, read{req: req, err: err})
+	}
+}
+
+// Middleware return endpoint middleware verifying signature of request read by HTTPToContext. It must be
+// wrapped by authentication: requests of API keys and requests carrying partner header must be signed,
+// requests of bearer tokens without partner header are passed through
+func Middleware(v *Verifier) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			res, ok := ctx.Value(requestKey{}).(read)
+			if !ok {
+				return next(ctx, request)
+			}
+			if _, isKey := auth.KeyFromContext(ctx); !isKey && res.req.Partner == "" {
+				return next(ctx, request)
+			}
+			if res.err != nil {
+				return nil, res.err
+			}
+			partner, err := v.Verify(res.req)
+			if err != nil {
+				return nil, err
+			}
+			return next(context.WithValue(ctx, partnerKey{}, partner), request)
+		}
+	}
+}
+
+// RejectKeys return endpoint middleware for transports not carrying signatures, e.g. gRPC. When signing is
+// enabled requests of API keys are rejected, partners must call signed routes over HTTP. Nil verifier
+// disables signing
+func RejectKeys(v *Verifier) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			if _, isKey := auth.KeyFromContext(ctx); v != nil && isKey {
+				return nil, ErrInvalidSignature{Reason: "signed requests are served over HTTP only"}
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// PartnerFromContext return ID of partner signed the request
+func PartnerFromContext(ctx context.Context) (string, bool) {
+	partner, ok := ctx.Value(partnerKey{}).(string)
+	return partner, ok
+}
@@ -0,0 +1,39 @@
+package signature
+
+import (
+	"sync"
+	"time"
+)
+
+// nonceCache remember nonces of served requests until their timestamps leave skew window, so a request can't be
+// replayed while it would pass timestamp check. The cache is local to the process, instances behind a load
+// balancer don't share nonces, so a request may be replayed once against each instance
+type nonceCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	seen      map[string]time.Time
+	nextSweep time.Time
+}
+
+func newNonceCache(ttl time.Duration) *nonceCache {
+	return &nonceCache{ttl: ttl, seen: make(map[string]time.Time)}
+}
+
+// add remember nonce, return false when it's already known
+func (c *nonceCache) add(nonce string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now.After(c.nextSweep) {
+		for n, exp := range c.seen {
+			if now.After(exp) {
+				delete(c.seen, n)
+			}
+		}
+		c.nextSweep = now.Add(c.ttl)
+	}
+	if exp, ok := c.seen[nonce]; ok && !now.After(exp) {
+		return false
+	}
+	c.seen[nonce] = now.Add(c.ttl)
+	return true
+}
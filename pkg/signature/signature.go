@@ -0,0 +1,169 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// headers of signed request
+const (
+	PartnerHeader   = "X-Partner-ID"
+	TimestampHeader = "X-Signature-Timestamp"
+	NonceHeader     = "X-Signature-Nonce"
+	SignatureHeader = "X-Signature"
+)
+
+// maxNonceLength longest accepted nonce
+const maxNonceLength = 64
+
+// MaxBodySize largest body of signed request, body is buffered in memory to verify signature
+const MaxBodySize = 1 << 20
+
+// DefaultSkew how far request timestamp may be from server time
+const DefaultSkew = 5 * time.Minute
+
+// Secret shared with partner. Secret is valid between NotBefore and NotAfter, zero time means unbounded, so
+// during rotation old and new secrets of partner are valid at the same time
+type Secret struct {
+	Partner   string    `json:"partner"`
+	Secret    string    `json:"secret"`
+	NotBefore time.Time `json:"not_before,omitempty"`
+	NotAfter  time.Time `json:"not_after,omitempty"`
+}
+
+// validAt return true when secret is valid at t
+func (s Secret) validAt(t time.Time) bool {
+	return (s.NotBefore.IsZero() || !t.Before(s.NotBefore)) && (s.NotAfter.IsZero() || t.Before(s.NotAfter))
+}
+
+// Verifier verify signatures of partner requests
+type Verifier struct {
+	secrets map[string][]Secret
+	skew    time.Duration
+	nonces  *nonceCache
+}
+
+// NewVerifier build verifier of secrets, timestamps of requests may differ from server time by skew
+func NewVerifier(secrets []Secret, skew time.Duration) *Verifier {
+	v := &Verifier{
+		secrets: make(map[string][]Secret),
+		skew:    skew,
+		nonces:  newNonceCache(2 * skew),
+	}
+	for _, s := range secrets {
+		v.secrets[s.Partner] = append(v.secrets[s.Partner], s)
+	}
+	return v
+}
+
+// LoadSecrets read JSON array of secrets from file
+func LoadSecrets(file string) ([]Secret, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read signing secrets")
+	}
+	var secrets []Secret
+	if err := json.Unmarshal(b, &secrets); err != nil {
+		return nil, errors.Wrap(err, "unable to parse signing secrets")
+	}
+	for _, s := range secrets {
+		if s.Partner == "" || s.Secret == "" {
+			return nil, errors.New("signing secret must have partner and secret")
+		}
+	}
+	return secrets, nil
+}
+
+// Sign return hex encoded HMAC-SHA256 of method, path, timestamp, nonce and body separated by new lines
+func Sign(secret, method, path, timestamp, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(method + "\n" + path + "\n" + timestamp + "\n" + nonce + "\n"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Request holds what signature of request is computed from
+type Request struct {
+	Partner   string
+	Timestamp string
+	Nonce     string
+	Signature string
+	Method    string
+	Path      string
+	Body      []byte
+}
+
+// ReadRequest read signature headers and body of r, body is replaced, so it can be decoded again. Body must
+// be limited by http.MaxBytesReader, see LimitBody
+func ReadRequest(r *http.Request) (Request, error) {
+	req := Request{
+		Partner:   r.Header.Get(PartnerHeader),
+		Timestamp: r.Header.Get(TimestampHeader),
+		Nonce:     r.Header.Get(NonceHeader),
+		Signature: r.Header.Get(SignatureHeader),
+		Method:    r.Method,
+		Path:      r.URL.Path,
+	}
+	if r.Body == nil {
+		return req, nil
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return req, ErrInvalidSignature{Reason: "unable to read body"}
+	}
+	req.Body = body
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return req, nil
+}
+
+// Verify check signature of request and return ID of partner signed it
+func (v *Verifier) Verify(req Request) (string, error) {
+	if req.Partner == "" || req.Timestamp == "" || req.Nonce == "" || req.Signature == "" {
+		return "", ErrInvalidSignature{Reason: "signature headers are required"}
+	}
+	if len(req.Nonce) > maxNonceLength {
+		return "", ErrInvalidSignature{Reason: "nonce is too long"}
+	}
+	secrets, ok := v.secrets[req.Partner]
+	if !ok {
+		return "", ErrInvalidSignature{Reason: "unknown partner"}
+	}
+	ts, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return "", ErrInvalidSignature{Reason: "timestamp must be unix time in seconds"}
+	}
+	now := time.Now()
+	if d := now.Sub(time.Unix(ts, 0)); d > v.skew || d < -v.skew {
+		return "", ErrInvalidSignature{Reason: "timestamp is out of allowed skew"}
+	}
+	if !match(secrets, now, req) {
+		return "", ErrInvalidSignature{Reason: "signature doesn't match"}
+	}
+	if !v.nonces.add(req.Partner+":"+req.Nonce, now) {
+		return "", ErrReplayed{Nonce: req.Nonce}
+	}
+	return req.Partner, nil
+}
+
+// match return true when signature is made with any secret valid at now
+func match(secrets []Secret, now time.Time, req Request) bool {
+	for _, s := range secrets {
+		if !s.validAt(now) {
+			continue
+		}
+		expected := Sign(s.Secret, req.Method, req.Path, req.Timestamp, req.Nonce, req.Body)
+		if hmac.Equal([]byte(expected), []byte(req.Signature)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package signature
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const testBody = `{"from":1,"to":2,"amount":"10.00"}`
+
+func signedRequest(secret string, ts time.Time, nonce string) Request {
+	timestamp := strconv.FormatInt(ts.Unix(), 10)
+	return Request{
+		Partner:   "acme",
+		Timestamp: timestamp,
+		Nonce:     nonce,
+		Signature: Sign(secret, "POST", "/payment/v1/transfer", timestamp, nonce, []byte(testBody)),
+		Method:    "POST",
+		Path:      "/payment/v1/transfer",
+		Body:      []byte(testBody),
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Now()
+	v := NewVerifier([]Secret{
+		{Partner: "acme", Secret: "old", NotAfter: now.Add(time.Hour)},
+		{Partner: "acme", Secret: "new", NotBefore: now.Add(-time.Hour)},
+		{Partner: "acme", Secret: "expired", NotAfter: now.Add(-time.Minute)},
+		{Partner: "acme", Secret: "future", NotBefore: now.Add(time.Minute)},
+	}, DefaultSkew)
+
+	tamperedBody := signedRequest("new", now, "n-body")
+	tamperedBody.Body = []byte(`{"from":1,"to":2,"amount":"1000.00"}`)
+	tamperedPath := signedRequest("new", now, "n-path")
+	tamperedPath.Path = "/payment/v1/topup"
+	unknown := signedRequest("new", now, "n-unknown")
+	unknown.Partner = "evil"
+	unsigned := signedRequest("new", now, "n-unsigned")
+	unsigned.Signature = ""
+	longNonce := signedRequest("new", now, string(bytes.Repeat([]byte("n"), maxNonceLength+1)))
+	badTimestamp := signedRequest("new", now, "n-ts")
+	badTimestamp.Timestamp = now.Format(time.RFC3339)
+
+	tests := []struct {
+		name string
+		req  Request
+		err  error
+	}{
+		{"old secret during rotation", signedRequest("old", now, "n1"), nil},
+		{"new secret during rotation", signedRequest("new", now, "n2"), nil},
+		{"expired secret", signedRequest("expired", now, "n3"), ErrInvalidSignature{Reason: "signature doesn't match"}},
+		{"secret not valid yet", signedRequest("future", now, "n4"), ErrInvalidSignature{Reason: "signature doesn't match"}},
+		{"wrong secret", signedRequest("guess", now, "n5"), ErrInvalidSignature{Reason: "signature doesn't match"}},
+		{"tampered body", tamperedBody, ErrInvalidSignature{Reason: "signature doesn't match"}},
+		{"tampered path", tamperedPath, ErrInvalidSignature{Reason: "signature doesn't match"}},
+		{"unknown partner", unknown, ErrInvalidSignature{Reason: "unknown partner"}},
+		{"missing signature", unsigned, ErrInvalidSignature{Reason: "signature headers are required"}},
+		{"long nonce", longNonce, ErrInvalidSignature{Reason: "nonce is too long"}},
+		{"timestamp isn't unix", badTimestamp, ErrInvalidSignature{Reason: "timestamp must be unix time in seconds"}},
+		{"timestamp in past", signedRequest("new", now.Add(-DefaultSkew-time.Minute), "n6"), ErrInvalidSignature{Reason: "timestamp is out of allowed skew"}},
+		{"timestamp in future", signedRequest("new", now.Add(DefaultSkew+time.Minute), "n7"), ErrInvalidSignature{Reason: "timestamp is out of allowed skew"}},
+		{"timestamp within skew", signedRequest("new", now.Add(-DefaultSkew+time.Minute), "n8"), nil},
+		{"replayed nonce", signedRequest("new", now, "n1"), ErrReplayed{Nonce: "n1"}},
+	}
+	for _, tt := range tests {
+		partner, err := v.Verify(tt.req)
+		if err != tt.err {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, tt.err)
+		}
+		if err == nil && partner != "acme" {
+			t.Errorf("%s: got partner %q", tt.name, partner)
+		}
+	}
+}
+
+func TestNonceOfBadSignatureIsNotRemembered(t *testing.T) {
+	now := time.Now()
+	v := NewVerifier([]Secret{{Partner: "acme", Secret: "secret"}}, DefaultSkew)
+	if _, err := v.Verify(signedRequest("guess", now, "n")); err == nil {
+		t.Fatal("bad signature is accepted")
+	}
+	if _, err := v.Verify(signedRequest("secret", now, "n")); err != nil {
+		t.Fatalf("nonce of rejected request is burnt: %v", err)
+	}
+}
+
+func TestNonceCacheExpiry(t *testing.T) {
+	c := newNonceCache(time.Minute)
+	now := time.Now()
+	if !c.add("n", now) {
+		t.Fatal("new nonce is rejected")
+	}
+	if c.add("n", now.Add(30*time.Second)) {
+		t.Fatal("nonce is accepted twice within ttl")
+	}
+	if !c.add("n", now.Add(2*time.Minute)) {
+		t.Fatal("nonce is rejected after ttl")
+	}
+}
+
+func TestReadRequest(t *testing.T) {
+	r := httptest.NewRequest("POST", "/payment/v1/transfer", bytes.NewBufferString(testBody))
+	r.Header.Set(PartnerHeader, "acme")
+	req, err := ReadRequest(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Partner != "acme" || req.Path != "/payment/v1/transfer" || string(req.Body) != testBody {
+		t.Fatalf("got %+v", req)
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil || string(body) != testBody {
+		t.Fatalf("body isn't restored: %q, %v", body, err)
+	}
+}
+
+func TestReadRequestLimit(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/payment/v1/transfer", bytes.NewReader(make([]byte, MaxBodySize+1)))
+	var err error
+	LimitBody(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		_, err = ReadRequest(r)
+	})).ServeHTTP(w, r)
+	if err == nil {
+		t.Fatal("body over limit is read")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	v := NewVerifier([]Secret{{Partner: "acme", Secret: "secret"}}, DefaultSkew)
+	next := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		partner, _ := PartnerFromContext(ctx)
+		return partner, nil
+	}
+	// bearer token callers without partner header don't sign
+	ctx := context.WithValue(context.Background(), requestKey{}, read{req: Request{Method: "POST"}})
+	if _, err := Middleware(v)(next)(ctx, nil); err != nil {
+		t.Fatalf("request without partner header is rejected: %v", err)
+	}
+	// partner header requires signature
+	ctx = context.WithValue(context.Background(), requestKey{}, read{req: Request{Partner: "acme"}})
+	if _, err := Middleware(v)(next)(ctx, nil); err == nil {
+		t.Fatal("unsigned request with partner header is accepted")
+	}
+	ctx = context.WithValue(context.Background(), requestKey{}, read{req: signedRequest("secret", time.Now(), "m")})
+	partner, err := Middleware(v)(next)(ctx, nil)
+	if err != nil || partner != "acme" {
+		t.Fatalf("got %v, %v", partner, err)
+	}
+}
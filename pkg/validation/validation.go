@@ -0,0 +1,170 @@
+package validation
+
+import (
+	"coins/pkg/metadata"
+	"coins/pkg/money"
+	"coins/pkg/problem"
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+// limits of metadata
+const (
+	MaxMetadataKeys      = 50
+	MaxMetadataKeyLength = 40
+)
+
+// machine-readable violation codes
+const (
+	CodeRequired    = "required"
+	CodeTooLong     = "too_long"
+	CodeNotPositive = "not_positive"
+	CodeNegative    = "negative"
+	CodeInvalid     = "invalid"
+	CodeSameAccount = "same_account"
+)
+
+// Violation of a single request field
+type Violation struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error raised when request has at least one invalid field, lists every violation
+type Error struct {
+	Violations []Violation
+}
+
+func (e Error) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, fmt.Sprintf("%s %s", v.Field, v.Message))
+	}
+	return "invalid request: " + strings.Join(msgs, ", ")
+}
+
+// Code implements problem.Coder
+func (e Error) Code() string {
+	return problem.CodeValidationFailed
+}
+
+// Extensions implements problem.Extender, every violation is listed in `violations` member
+func (e Error) Extensions() map[string]interface{} {
+	return map[string]interface{}{"violations": e.Violations}
+}
+
+// Validator implemented by requests which can check their fields
+type Validator interface {
+	Validate() error
+}
+
+// Middleware validate requests implementing Validator before they reach the endpoint
+func Middleware(next endpoint.Endpoint) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		if v, ok := request.(Validator); ok {
+			if err := v.Validate(); err != nil {
+				return nil, err
+			}
+		}
+		return next(ctx, request)
+	}
+}
+
+// Violations collects violations of request fields
+type Violations []Violation
+
+// Add violation of field
+func (vv *Violations) Add(field, code, message string) {
+	*vv = append(*vv, Violation{Field: field, Code: code, Message: message})
+}
+
+// Err return Error listing collected violations or nil when there are none
+func (vv Violations) Err() error {
+	if len(vv) == 0 {
+		return nil
+	}
+	return Error{Violations: vv}
+}
+
+// Name check required name fits column of `max` characters
+func (vv *Violations) Name(field, value string, max int) {
+	if strings.TrimSpace(value) == "" {
+		vv.Add(field, CodeRequired, "is required")
+		return
+	}
+	vv.MaxLength(field, value, max)
+}
+
+// MaxLength check value is at most `max` characters
+func (vv *Violations) MaxLength(field, value string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		vv.Add(field, CodeTooLong, fmt.Sprintf("must be at most %d characters", max))
+	}
+}
+
+// Email check required email address
+func (vv *Violations) Email(field, value string, max int) {
+	if value == "" {
+		vv.Add(field, CodeRequired, "is required")
+		return
+	}
+	if a, err := mail.ParseAddress(value); err != nil || a.Address != value {
+		vv.Add(field, CodeInvalid, "must be email address")
+		return
+	}
+	vv.MaxLength(field, value, max)
+}
+
+// ID check required ID of entity
+func (vv *Violations) ID(field string, id int64) {
+	if id <= 0 {
+		vv.Add(field, CodeRequired, "must be positive ID")
+	}
+}
+
+// Positive check amount is greater than zero
+func (vv *Violations) Positive(field string, a money.Amount) {
+	if !a.IsPositive() {
+		vv.Add(field, CodeNotPositive, "must be positive")
+	}
+}
+
+// NotNegative check amount isn't less than zero, zero amount is allowed where it has meaning, like full capture
+func (vv *Violations) NotNegative(field string, a money.Amount) {
+	if a.IsNegative() {
+		vv.Add(field, CodeNegative, "must not be negative")
+	}
+}
+
+// Currency check required currency
+func (vv *Violations) Currency(field string, c money.Currency) {
+	if c == "" {
+		vv.Add(field, CodeRequired, "is required")
+	}
+}
+
+// DifferentAccounts check money doesn't go from account to itself
+func (vv *Violations) DifferentAccounts(field string, from, to int64) {
+	if from != 0 && from == to {
+		vv.Add(field, CodeSameAccount, "must differ from source account")
+	}
+}
+
+// Metadata check metadata has at most MaxMetadataKeys keys of at most MaxMetadataKeyLength characters
+func (vv *Violations) Metadata(field string, md metadata.Metadata) {
+	if len(md) > MaxMetadataKeys {
+		vv.Add(field, CodeTooLong, fmt.Sprintf("must have at most %d keys", MaxMetadataKeys))
+	}
+	for k := range md {
+		if k == "" || utf8.RuneCountInString(k) > MaxMetadataKeyLength {
+			vv.Add(field, CodeInvalid, fmt.Sprintf("keys must be 1 to %d characters", MaxMetadataKeyLength))
+			return
+		}
+	}
+}
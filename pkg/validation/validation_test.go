@@ -0,0 +1,97 @@
+package validation
+
+import (
+	"coins/pkg/metadata"
+	"coins/pkg/money"
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func codes(vv Violations) []string {
+	var cc []string
+	for _, v := range vv {
+		cc = append(cc, v.Field+":"+v.Code)
+	}
+	return cc
+}
+
+func TestViolations(t *testing.T) {
+	manyKeys := metadata.Metadata{}
+	for i := 0; i <= MaxMetadataKeys; i++ {
+		manyKeys["k"+strconv.Itoa(i)] = "v"
+	}
+	tests := []struct {
+		name  string
+		check func(vv *Violations)
+		want  []string
+	}{
+		{"name", func(vv *Violations) { vv.Name("name", "alice", 5) }, nil},
+		{"blank name", func(vv *Violations) { vv.Name("name", "  ", 5) }, []string{"name:required"}},
+		{"long name", func(vv *Violations) { vv.Name("name", "alice!", 5) }, []string{"name:too_long"}},
+		{"length in characters", func(vv *Violations) { vv.MaxLength("name", "żółw", 4) }, nil},
+		{"email", func(vv *Violations) { vv.Email("email", "a@example.com", 50) }, nil},
+		{"missing email", func(vv *Violations) { vv.Email("email", "", 50) }, []string{"email:required"}},
+		{"invalid email", func(vv *Violations) { vv.Email("email", "alice", 50) }, []string{"email:invalid"}},
+		{"email with name", func(vv *Violations) { vv.Email("email", "Alice <a@example.com>", 50) }, []string{"email:invalid"}},
+		{"long email", func(vv *Violations) { vv.Email("email", "a@example.com", 5) }, []string{"email:too_long"}},
+		{"ID", func(vv *Violations) { vv.ID("id", 1) }, nil},
+		{"zero ID", func(vv *Violations) { vv.ID("id", 0) }, []string{"id:required"}},
+		{"positive", func(vv *Violations) { vv.Positive("amount", 1) }, nil},
+		{"zero amount", func(vv *Violations) { vv.Positive("amount", 0) }, []string{"amount:not_positive"}},
+		{"zero not negative", func(vv *Violations) { vv.NotNegative("amount", 0) }, nil},
+		{"negative", func(vv *Violations) { vv.NotNegative("amount", money.Amount(-1)) }, []string{"amount:negative"}},
+		{"currency", func(vv *Violations) { vv.Currency("currency", "") }, []string{"currency:required"}},
+		{"different accounts", func(vv *Violations) { vv.DifferentAccounts("to", 1, 2) }, nil},
+		{"same account", func(vv *Violations) { vv.DifferentAccounts("to", 1, 1) }, []string{"to:same_account"}},
+		{"missing accounts", func(vv *Violations) { vv.DifferentAccounts("to", 0, 0) }, nil},
+		{"metadata", func(vv *Violations) { vv.Metadata("metadata", metadata.Metadata{"order": "1"}) }, nil},
+		{"too many keys", func(vv *Violations) { vv.Metadata("metadata", manyKeys) }, []string{"metadata:too_long"}},
+		{"empty key", func(vv *Violations) { vv.Metadata("metadata", metadata.Metadata{"": "1"}) }, []string{"metadata:invalid"}},
+		{"long key", func(vv *Violations) {
+			vv.Metadata("metadata", metadata.Metadata{strings.Repeat("k", MaxMetadataKeyLength+1): "1"})
+		}, []string{"metadata:invalid"}},
+		{"every violation", func(vv *Violations) {
+			vv.ID("from", 0)
+			vv.Positive("amount", 0)
+		}, []string{"from:required", "amount:not_positive"}},
+	}
+	for _, tt := range tests {
+		var vv Violations
+		tt.check(&vv)
+		if got := codes(vv); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+		if err := vv.Err(); (err == nil) != (tt.want == nil) {
+			t.Errorf("%s: got error %v", tt.name, err)
+		}
+	}
+}
+
+type request struct {
+	err error
+}
+
+func (r request) Validate() error {
+	return r.err
+}
+
+func TestMiddleware(t *testing.T) {
+	called := false
+	e := Middleware(func(context.Context, interface{}) (interface{}, error) {
+		called = true
+		return nil, nil
+	})
+	invalid := Error{Violations: []Violation{{Field: "amount", Code: CodeNotPositive, Message: "must be positive"}}}
+	if _, err := e(context.Background(), request{err: invalid}); !reflect.DeepEqual(err, invalid) || called {
+		t.Fatalf("invalid request: got %v, endpoint called %v", err, called)
+	}
+	if _, err := e(context.Background(), request{}); err != nil || !called {
+		t.Fatalf("valid request: got %v, endpoint called %v", err, called)
+	}
+	if invalid.Error() != "invalid request: amount must be positive" {
+		t.Fatalf("got message %q", invalid.Error())
+	}
+}
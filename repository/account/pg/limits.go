@@ -0,0 +1,81 @@
+package pg
+
+import (
+	"coins/pkg/account"
+	"coins/pkg/money"
+	"context"
+
+	"github.com/doug-martin/goqu/v8"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+const tableLimits = "spending_limit"
+
+type recordLimits struct {
+	AccountID      int64          `db:"account_id"`
+	Currency       money.Currency `db:"currency"`
+	Overdraft      *money.Amount  `db:"overdraft"`
+	MinBalance     *money.Amount  `db:"min_balance"`
+	MaxTransaction *money.Amount  `db:"max_transaction"`
+	DailyOutflow   *money.Amount  `db:"daily_outflow"`
+	MonthlyOutflow *money.Amount  `db:"monthly_outflow"`
+}
+
+func (r *recordLimits) toLimits() *account.Limits {
+	return &account.Limits{
+		Currency:       r.Currency,
+		Overdraft:      r.Overdraft,
+		MinBalance:     r.MinBalance,
+		MaxTransaction: r.MaxTransaction,
+		DailyOutflow:   r.DailyOutflow,
+		MonthlyOutflow: r.MonthlyOutflow,
+	}
+}
+
+// GetLimits return spending limits of account wallets ordered by currency
+func (repo *repository) GetLimits(ctx context.Context, id int64) ([]*account.Limits, error) {
+	var rr []*recordLimits
+	err := repo.gq.From(tableLimits).Where(goqu.I("account_id").Eq(id)).Order(goqu.I("currency").Asc()).ScanStructsContext(ctx, &rr)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get spending limits")
+	}
+	limits := make([]*account.Limits, 0, len(rr))
+	for _, r := range rr {
+		limits = append(limits, r.toLimits())
+	}
+	return limits, nil
+}
+
+// StoreLimits insert spending limits of account wallet or replace existing ones
+func (repo *repository) StoreLimits(ctx context.Context, id int64, l *account.Limits) error {
+	settings := goqu.Record{
+		"overdraft":       nullAmount(l.Overdraft),
+		"min_balance":     nullAmount(l.MinBalance),
+		"max_transaction": nullAmount(l.MaxTransaction),
+		"daily_outflow":   nullAmount(l.DailyOutflow),
+		"monthly_outflow": nullAmount(l.MonthlyOutflow),
+	}
+	row := goqu.Record{"account_id": id, "currency": l.Currency}
+	for k, v := range settings {
+		row[k] = v
+	}
+	_, err := repo.gq.From(tableLimits).Insert().Rows(row).
+		OnConflict(goqu.DoUpdate("account_id, currency", settings)).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == foreignKeyViolation {
+			return account.ErrNotFound{ID: id}
+		}
+		return errors.Wrap(err, "unable to store spending limits")
+	}
+	return nil
+}
+
+// nullAmount return NULL for nil amount, goqu can't pass nil *money.Amount to its driver.Valuer
+func nullAmount(a *money.Amount) interface{} {
+	if a == nil {
+		return nil
+	}
+	return *a
+}
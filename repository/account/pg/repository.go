@@ -2,38 +2,76 @@ package pg
 
 import (
 	"coins/pkg/account"
+	"coins/pkg/metadata"
 	"context"
 	"database/sql"
+	"strings"
 
 	"github.com/doug-martin/goqu/v8"
 	_ "github.com/doug-martin/goqu/v8/dialect/postgres"
+	"github.com/doug-martin/goqu/v8/exp"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 )
 
 const table = "account"
 
+// likeEscaper escape LIKE wildcards of search query
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 type record struct {
-	ID        int64  `db:"id" goqu:"skipinsert,skipupdate"`
-	FirstName string `db:"first_name"`
-	LastName  string `db:"last_name"`
+	ID         int64             `db:"id" goqu:"skipinsert,skipupdate"`
+	CustomerID *int64            `db:"customer_id"`
+	FirstName  string            `db:"first_name"`
+	LastName   string            `db:"last_name"`
+	Status     account.Status    `db:"status"`
+	Tier       account.Tier      `db:"tier"`
+	ExternalID *string           `db:"external_id"`
+	Metadata   metadata.Metadata `db:"metadata"`
 }
 
 func (t *record) toAccount() *account.Account {
-	return &account.Account{
+	a := &account.Account{
 		ID:        t.ID,
 		FirstName: t.FirstName,
 		LastName:  t.LastName,
+		Status:    t.Status,
+		Tier:      t.Tier,
+		Metadata:  t.Metadata,
+	}
+	if t.CustomerID != nil {
+		a.CustomerID = *t.CustomerID
+	}
+	if t.ExternalID != nil {
+		a.ExternalID = *t.ExternalID
 	}
+	return a
 }
 
 func fromAccount(a *account.Account) *record {
-	return &record{
+	r := &record{
 		ID:        a.ID,
 		FirstName: a.FirstName,
 		LastName:  a.LastName,
+		Status:    a.Status,
+		Tier:      a.Tier,
+		Metadata:  a.Metadata,
+	}
+	if a.CustomerID != 0 {
+		r.CustomerID = &a.CustomerID
+	}
+	if a.ExternalID != "" {
+		r.ExternalID = &a.ExternalID
 	}
+	return r
 }
 
+// postgres error codes
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
 type repository struct {
 	gq *goqu.Database
 }
@@ -43,17 +81,73 @@ func NewRepository(db *sql.DB) account.Repository {
 	return &repository{gq: goqu.New("postgres", db)}
 }
 
-func (repo *repository) List(ctx context.Context) ([]*account.Account, error) {
+func (repo *repository) List(ctx context.Context, f account.ListFilter) (*account.Page, error) {
+	q := repo.gq.From(table)
+	if f.CustomerID != 0 {
+		q = q.Where(goqu.I("customer_id").Eq(f.CustomerID))
+	}
+	if f.ExternalID != "" {
+		q = q.Where(goqu.I("external_id").Eq(f.ExternalID))
+	}
+	if len(f.Metadata) > 0 {
+		q = q.Where(goqu.L("? @> ?::jsonb", goqu.I("metadata"), f.Metadata.JSON()))
+	}
+	if f.Query != "" {
+		prefix := likeEscaper.Replace(strings.ToLower(f.Query)) + "%"
+		q = q.Where(goqu.Or(
+			goqu.L("lower(?) LIKE ?", goqu.I("first_name"), prefix),
+			goqu.L("lower(?) LIKE ?", goqu.I("last_name"), prefix),
+		))
+	}
+
+	page := &account.Page{}
+	if f.WithTotal {
+		total, err := q.CountContext(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to count account records")
+		}
+		page.Total = &total
+	}
+
+	// keyset on (sort field, id), id alone when sorted by id
+	cmp, order := ">", exp.IdentifierExpression.Asc
+	if f.Order == account.OrderDesc {
+		cmp, order = "<", exp.IdentifierExpression.Desc
+	}
+	if f.Sort == account.SortByID {
+		if f.After != nil {
+			q = q.Where(goqu.L("? "+cmp+" ?", goqu.I("id"), f.After.ID))
+		}
+	} else {
+		if f.After != nil {
+			q = q.Where(goqu.L("(?, ?) "+cmp+" (?, ?)", goqu.I(string(f.Sort)), goqu.I("id"), f.After.Value, f.After.ID))
+		}
+		q = q.OrderAppend(order(goqu.I(string(f.Sort))))
+	}
+	q = q.OrderAppend(order(goqu.I("id")))
+
+	// one extra record tells whether there is the next page
 	var rr []*record
-	if err := repo.gq.From(table).Order(goqu.I("id").Asc()).ScanStructsContext(ctx, &rr); err != nil {
+	if err := q.Limit(uint(f.Limit+1)).ScanStructsContext(ctx, &rr); err != nil {
 		return nil, errors.Wrap(err, "unable to retrieve account records")
 	}
-	aa := make([]*account.Account, 0, len(rr))
+	if len(rr) > f.Limit {
+		rr = rr[:f.Limit]
+		last := rr[len(rr)-1]
+		page.Next = &account.Cursor{ID: last.ID}
+		switch f.Sort {
+		case account.SortByFirstName:
+			page.Next.Value = last.FirstName
+		case account.SortByLastName:
+			page.Next.Value = last.LastName
+		}
+	}
+	page.Accounts = make([]*account.Account, 0, len(rr))
 	for _, r := range rr {
-		aa = append(aa, r.toAccount())
+		page.Accounts = append(page.Accounts, r.toAccount())
 	}
 
-	return aa, nil
+	return page, nil
 }
 func (repo *repository) Get(ctx context.Context, id int64) (*account.Account, error) {
 	r := &record{}
@@ -70,9 +164,56 @@ func (repo *repository) Store(ctx context.Context, a *account.Account) (*account
 	r := fromAccount(a)
 	res := repo.gq.From(table).Insert().Returning(goqu.C("id")).Rows(r).Executor()
 	var id int64
-	if _, err := res.ScanVal(&id); err != nil {
+	if _, err := res.ScanValContext(ctx, &id); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code {
+			case foreignKeyViolation:
+				return nil, account.ErrCustomerNotFound{ID: a.CustomerID}
+			case uniqueViolation:
+				return nil, account.ErrDuplicateExternalID{ExternalID: a.ExternalID}
+			}
+		}
 		return nil, errors.Wrap(err, "failed to retrieve last inserted ID")
 	}
 	a.ID = id
 	return a, nil
 }
+
+func (repo *repository) Update(ctx context.Context, a *account.Account) (*account.Account, error) {
+	res, err := repo.gq.Update(table).
+		Set(goqu.Record{"first_name": a.FirstName, "last_name": a.LastName}).
+		Where(goqu.I("id").Eq(a.ID)).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to update account")
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, errors.Wrap(err, "unable to update account")
+	} else if n == 0 {
+		return nil, account.ErrNotFound{ID: a.ID}
+	}
+	return a, nil
+}
+
+// SetStatus change status only when account is still in status `from`, so concurrent transitions can't both succeed
+func (repo *repository) SetStatus(ctx context.Context, id int64, from, to account.Status) error {
+	res, err := repo.gq.Update(table).
+		Set(goqu.Record{"status": to}).
+		Where(goqu.Ex{"id": id, "status": from}).
+		Executor().ExecContext(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to update account status")
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "unable to update account status")
+	}
+	if n > 0 {
+		return nil
+	}
+	a, err := repo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	return account.ErrInvalidTransition{ID: id, From: a.Status, To: to}
+}
@@ -0,0 +1,113 @@
+package pg
+
+import (
+	"coins/pkg/auth"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/doug-martin/goqu/v8"
+	_ "github.com/doug-martin/goqu/v8/dialect/postgres"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+const table = "api_key"
+
+type record struct {
+	ID        int64          `db:"id" goqu:"skipinsert,skipupdate"`
+	Name      string         `db:"name"`
+	Prefix    string         `db:"prefix"`
+	Hash      string         `db:"hash"`
+	Scopes    pq.StringArray `db:"scopes"`
+	CreatedAt time.Time      `db:"created_at"`
+	RevokedAt *time.Time     `db:"revoked_at"`
+}
+
+func (r *record) toKey() *auth.Key {
+	k := &auth.Key{
+		ID:        r.ID,
+		Name:      r.Name,
+		Prefix:    r.Prefix,
+		Hash:      r.Hash,
+		CreatedAt: r.CreatedAt,
+		RevokedAt: r.RevokedAt,
+	}
+	for _, s := range r.Scopes {
+		k.Scopes = append(k.Scopes, auth.Scope(s))
+	}
+	return k
+}
+
+func fromKey(k *auth.Key) *record {
+	r := &record{
+		ID:        k.ID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Hash:      k.Hash,
+		CreatedAt: k.CreatedAt,
+		RevokedAt: k.RevokedAt,
+	}
+	for _, s := range k.Scopes {
+		r.Scopes = append(r.Scopes, string(s))
+	}
+	return r
+}
+
+type repository struct {
+	gq *goqu.Database
+}
+
+// NewRepository - build new repository
+func NewRepository(db *sql.DB) auth.Repository {
+	return &repository{gq: goqu.New("postgres", db)}
+}
+
+func (repo *repository) List(ctx context.Context) ([]*auth.Key, error) {
+	var rr []*record
+	if err := repo.gq.From(table).Order(goqu.I("id").Asc()).ScanStructsContext(ctx, &rr); err != nil {
+		return nil, errors.Wrap(err, "unable to list API keys")
+	}
+	keys := make([]*auth.Key, 0, len(rr))
+	for _, r := range rr {
+		keys = append(keys, r.toKey())
+	}
+	return keys, nil
+}
+
+func (repo *repository) GetByPrefix(ctx context.Context, prefix string) (*auth.Key, error) {
+	r := &record{}
+	found, err := repo.gq.From(table).Where(goqu.I("prefix").Eq(prefix)).ScanStructContext(ctx, r)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get API key")
+	}
+	if !found {
+		return nil, auth.ErrUnauthenticated{}
+	}
+	return r.toKey(), nil
+}
+
+func (repo *repository) Store(ctx context.Context, k *auth.Key) (*auth.Key, error) {
+	res := repo.gq.From(table).Insert().Returning(goqu.C("id")).Rows(fromKey(k)).Executor()
+	if _, err := res.ScanValContext(ctx, &k.ID); err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve last inserted ID")
+	}
+	return k, nil
+}
+
+// Revoke keep revocation time of already revoked key
+func (repo *repository) Revoke(ctx context.Context, id int64, at time.Time) (*auth.Key, error) {
+	r := &record{}
+	found, err := repo.gq.Update(table).
+		Set(goqu.Record{"revoked_at": goqu.COALESCE(goqu.I("revoked_at"), at)}).
+		Where(goqu.I("id").Eq(id)).
+		Returning(&record{}).
+		Executor().ScanStructContext(ctx, r)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to revoke API key")
+	}
+	if !found {
+		return nil, auth.ErrKeyNotFound{ID: id}
+	}
+	return r.toKey(), nil
+}
@@ -0,0 +1,71 @@
+package pg
+
+import (
+	"coins/pkg/customer"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/doug-martin/goqu/v8"
+	_ "github.com/doug-martin/goqu/v8/dialect/postgres"
+	"github.com/pkg/errors"
+)
+
+const table = "customer"
+
+type record struct {
+	ID        int64     `db:"id" goqu:"skipinsert,skipupdate"`
+	FirstName string    `db:"first_name"`
+	LastName  string    `db:"last_name"`
+	Email     string    `db:"email"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (r *record) toCustomer() *customer.Customer {
+	return &customer.Customer{
+		ID:        r.ID,
+		FirstName: r.FirstName,
+		LastName:  r.LastName,
+		Email:     r.Email,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func fromCustomer(c *customer.Customer) *record {
+	return &record{
+		ID:        c.ID,
+		FirstName: c.FirstName,
+		LastName:  c.LastName,
+		Email:     c.Email,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+type repository struct {
+	gq *goqu.Database
+}
+
+// NewRepository - build new repository
+func NewRepository(db *sql.DB) customer.Repository {
+	return &repository{gq: goqu.New("postgres", db)}
+}
+
+func (repo *repository) Get(ctx context.Context, id int64) (*customer.Customer, error) {
+	r := &record{}
+	found, err := repo.gq.From(table).Where(goqu.I("id").Eq(id)).ScanStructContext(ctx, r)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get customer")
+	}
+	if !found {
+		return nil, customer.ErrNotFound{ID: id}
+	}
+	return r.toCustomer(), nil
+}
+
+func (repo *repository) Store(ctx context.Context, c *customer.Customer) (*customer.Customer, error) {
+	res := repo.gq.From(table).Insert().Returning(goqu.C("id")).Rows(fromCustomer(c)).Executor()
+	if _, err := res.ScanValContext(ctx, &c.ID); err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve last inserted ID")
+	}
+	return c, nil
+}
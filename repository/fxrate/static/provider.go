@@ -0,0 +1,79 @@
+package static
+
+import (
+	"coins/pkg/money"
+	"coins/pkg/payment"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type pair struct {
+	from money.Currency
+	to   money.Currency
+}
+
+type provider struct {
+	rates map[pair]money.Rate
+}
+
+// NewProvider - build FXRateProvider with fixed rates keyed by "FROM/TO" pair, e.g. "EUR/USD".
+// Rate of the opposite direction is derived when it isn't provided explicitly
+func NewProvider(rates map[string]money.Rate) (payment.FXRateProvider, error) {
+	p := &provider{rates: make(map[pair]money.Rate, len(rates)*2)}
+	for key, rate := range rates {
+		codes := strings.Split(key, "/")
+		if len(codes) != 2 {
+			return nil, errors.Errorf("invalid currency pair %q", key)
+		}
+		from, err := money.ParseCurrency(codes[0])
+		if err != nil {
+			return nil, err
+		}
+		to, err := money.ParseCurrency(codes[1])
+		if err != nil {
+			return nil, err
+		}
+		p.rates[pair{from: from, to: to}] = rate
+	}
+	for k, rate := range p.rates {
+		inverse := pair{from: k.to, to: k.from}
+		if _, ok := p.rates[inverse]; !ok {
+			r, err := rate.Inverse()
+			if err != nil {
+				return nil, err
+			}
+			p.rates[inverse] = r
+		}
+	}
+	return p, nil
+}
+
+// NewFileProvider - build FXRateProvider with rates loaded from JSON file like {"EUR/USD": "1.0845"}
+func NewFileProvider(path string) (payment.FXRateProvider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open rates file")
+	}
+	defer f.Close()
+
+	var rates map[string]money.Rate
+	if err := json.NewDecoder(f).Decode(&rates); err != nil {
+		return nil, errors.Wrapf(err, "unable to decode rates file %s", path)
+	}
+	return NewProvider(rates)
+}
+
+func (p *provider) Rate(_ context.Context, from, to money.Currency) (money.Rate, error) {
+	if from == to {
+		return money.OneRate, nil
+	}
+	rate, ok := p.rates[pair{from: from, to: to}]
+	if !ok {
+		return 0, payment.ErrRateUnavailable{From: from, To: to}
+	}
+	return rate, nil
+}
@@ -0,0 +1,121 @@
+package pg
+
+import (
+	"coins/pkg/account"
+	"coins/pkg/money"
+	"coins/pkg/payment"
+	"context"
+	"time"
+
+	"github.com/doug-martin/goqu/v8"
+	"github.com/doug-martin/goqu/v8/exp"
+	"github.com/pkg/errors"
+)
+
+const tableAccount = "account"
+
+type recordAccountStatus struct {
+	ID     int64          `db:"id"`
+	Status account.Status `db:"status"`
+}
+
+// checkAccounts raise account.ErrFrozen or account.ErrClosed when any of non-system accounts can't be debited
+// or credited. Account rows are share-locked until the end of transaction, so status can't change meanwhile
+func checkAccounts(ctx context.Context, tx *goqu.TxDatabase, accountIDs ...int64) error {
+	ids := make([]int64, 0, len(accountIDs))
+	for _, id := range accountIDs {
+		if !payment.IsSystemAccount(id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var rr []*recordAccountStatus
+	if err := tx.From(tableAccount).Where(goqu.I("id").In(ids)).ForShare(exp.Wait).ScanStructsContext(ctx, &rr); err != nil {
+		return errors.Wrap(err, "unable to get account status")
+	}
+	statuses := make(map[int64]account.Status, len(rr))
+	for _, r := range rr {
+		statuses[r.ID] = r.Status
+	}
+	for _, id := range ids {
+		switch status, ok := statuses[id]; {
+		case !ok:
+			return account.ErrNotFound{ID: id}
+		case status == account.StatusFrozen:
+			return account.ErrFrozen{ID: id}
+		case status == account.StatusClosed:
+			return account.ErrClosed{ID: id}
+		}
+	}
+	return nil
+}
+
+// Sweep - transfer all balances of closed account `from` to account `to`. Account with active holds
+// or pending withdrawals can't be swept, with zero `to` any funds raise account.ErrNonZeroBalance
+func (repo *repository) Sweep(ctx context.Context, from, to int64) ([]*payment.Transaction, error) {
+	var tt []*payment.Transaction
+	err := repo.gq.WithTx(func(tx *goqu.TxDatabase) error {
+		if err := lockBalances(ctx, tx, from, to); err != nil {
+			return err
+		}
+		if to != 0 {
+			if err := checkAccounts(ctx, tx, to); err != nil {
+				return err
+			}
+		}
+
+		holds, err := tx.From(tableHold).Where(activeHolds(from)).CountContext(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to count active holds")
+		}
+		pending, err := tx.From(tableTransaction).
+			Where(goqu.Ex{"from": from, "kind": payment.KindWithdrawal, "status": payment.StatusPending}).
+			CountContext(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to count pending withdrawals")
+		}
+		if holds > 0 || pending > 0 {
+			return account.ErrNonZeroBalance{ID: from}
+		}
+
+		var bb []*recordBalance
+		if err := tx.From(tableBalance).
+			Where(goqu.I("account_id").Eq(from), goqu.I("balance").Neq(0)).
+			Order(goqu.I("currency").Asc()).
+			ScanStructsContext(ctx, &bb); err != nil {
+			return errors.Wrap(err, "unable to retrieve balance records")
+		}
+		for _, b := range bb {
+			if to == 0 || b.Balance.IsNegative() {
+				return account.ErrNonZeroBalance{ID: from}
+			}
+			t := &payment.Transaction{
+				Kind:       payment.KindSweep,
+				Status:     payment.StatusCompleted,
+				From:       from,
+				To:         to,
+				Amount:     b.Balance,
+				Currency:   b.Currency,
+				ToAmount:   b.Balance,
+				ToCurrency: b.Currency,
+				Rate:       money.OneRate,
+				Date:       time.Now().UTC(),
+			}
+			if err := insertTransaction(ctx, tx, t); err != nil {
+				return err
+			}
+			if err := post(ctx, tx, &t.ID, t.Date, transferPostings(t)...); err != nil {
+				return err
+			}
+			tt = append(tt, t)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tt, nil
+}
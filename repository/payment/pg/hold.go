@@ -0,0 +1,221 @@
+package pg
+
+import (
+	"coins/pkg/money"
+	"coins/pkg/payment"
+	"context"
+	"time"
+
+	"github.com/doug-martin/goqu/v8"
+	"github.com/doug-martin/goqu/v8/exp"
+	"github.com/pkg/errors"
+)
+
+const tableHold = "hold"
+
+type recordHold struct {
+	ID            int64              `db:"id" goqu:"skipinsert,skipupdate"`
+	From          int64              `db:"from"`
+	To            int64              `db:"to"`
+	Amount        money.Amount       `db:"amount"`
+	Currency      money.Currency     `db:"currency"`
+	Status        payment.HoldStatus `db:"status"`
+	TransactionID *int64             `db:"transaction_id"`
+	CreatedAt     time.Time          `db:"created_at"`
+	ExpiresAt     time.Time          `db:"expires_at"`
+}
+
+// toHold convert record into Hold, active hold past its TTL reported as expired
+func (h *recordHold) toHold() *payment.Hold {
+	hold := &payment.Hold{
+		ID:        h.ID,
+		From:      h.From,
+		To:        h.To,
+		Amount:    h.Amount,
+		Currency:  h.Currency,
+		Status:    h.Status,
+		CreatedAt: h.CreatedAt,
+		ExpiresAt: h.ExpiresAt,
+	}
+	if h.TransactionID != nil {
+		hold.TransactionID = *h.TransactionID
+	}
+	if h.Status == payment.HoldActive && !time.Now().UTC().Before(h.ExpiresAt) {
+		hold.Status = payment.HoldExpired
+	}
+	return hold
+}
+
+func fromHold(h *payment.Hold) *recordHold {
+	return &recordHold{
+		ID:        h.ID,
+		From:      h.From,
+		To:        h.To,
+		Amount:    h.Amount,
+		Currency:  h.Currency,
+		Status:    h.Status,
+		CreatedAt: h.CreatedAt,
+		ExpiresAt: h.ExpiresAt,
+	}
+}
+
+// activeHolds select active holds which aren't expired yet
+func activeHolds(accountID int64) exp.ExpressionList {
+	return goqu.And(
+		goqu.I("from").Eq(accountID),
+		goqu.I("status").Eq(payment.HoldActive),
+		goqu.I("expires_at").Gt(time.Now().UTC()),
+	)
+}
+
+// heldAmount return sum of active holds of account wallet
+func heldAmount(ctx context.Context, tx *goqu.TxDatabase, accountID int64, currency money.Currency) (money.Amount, error) {
+	var held money.Amount
+	_, err := tx.From(tableHold).
+		Select(goqu.COALESCE(goqu.SUM("amount"), 0)).
+		Where(activeHolds(accountID), goqu.I("currency").Eq(currency)).
+		ScanValContext(ctx, &held)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to get held amount")
+	}
+	return held, nil
+}
+
+// Authorize - place hold reducing available balance of `From`, raise ErrInsufficientFunds and ErrLimitExceeded when
+// the hold breaks velocity limit of account tier or spending limits of its wallet
+func (repo *repository) Authorize(ctx context.Context, h *payment.Hold) (*payment.Hold, error) {
+	err := repo.gq.WithTx(func(tx *goqu.TxDatabase) error {
+		if err := lockBalance(ctx, tx, h.From); err != nil {
+			return err
+		}
+		if err := checkAccounts(ctx, tx, h.From, h.To); err != nil {
+			return err
+		}
+		if err := checkVelocity(ctx, tx, h.From, h.Currency, h.Amount, false); err != nil {
+			return err
+		}
+		if err := checkSpending(ctx, tx, h.From, h.Currency, h.Amount); err != nil {
+			return err
+		}
+		if err := checkFunds(ctx, tx, h.From, h.Currency, h.Amount); err != nil {
+			return err
+		}
+		res := tx.From(tableHold).Insert().Returning(goqu.C("id")).Rows(fromHold(h)).Executor()
+		if _, err := res.ScanValContext(ctx, &h.ID); err != nil {
+			return errors.Wrap(err, "failed to retrieve last inserted ID")
+		}
+		return nil
+	})
+	return h, err
+}
+
+// getActiveHold lock hold for update and check it can be captured or voided
+func getActiveHold(ctx context.Context, tx *goqu.TxDatabase, id int64) (*recordHold, error) {
+	r := &recordHold{}
+	found, err := tx.From(tableHold).Where(goqu.I("id").Eq(id)).ForUpdate(exp.Wait).ScanStructContext(ctx, r)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get hold")
+	}
+	if !found {
+		return nil, payment.ErrHoldNotFound{ID: id}
+	}
+	switch h := r.toHold(); h.Status {
+	case payment.HoldActive:
+		return r, nil
+	case payment.HoldExpired:
+		return nil, payment.ErrHoldExpired{ID: id}
+	default:
+		return nil, payment.ErrHoldNotActive{ID: id, Status: h.Status}
+	}
+}
+
+// Capture - release hold and transfer captured part of it, the rest of the hold becomes available again.
+// Zero amount captures the whole hold
+func (repo *repository) Capture(ctx context.Context, id int64, amount money.Amount) (*payment.Transaction, error) {
+	var t *payment.Transaction
+	err := repo.gq.WithTx(func(tx *goqu.TxDatabase) error {
+		h, err := getActiveHold(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if amount == 0 {
+			amount = h.Amount
+		}
+		if amount > h.Amount {
+			return payment.ErrCaptureExceedsHold{ID: id, Amount: h.Amount}
+		}
+
+		if err := lockBalances(ctx, tx, h.From, h.To); err != nil {
+			return err
+		}
+		if err := checkAccounts(ctx, tx, h.From, h.To); err != nil {
+			return err
+		}
+		// captured hold stops counting towards outflow caps before the capture is checked against them
+		if err := setHoldStatus(ctx, tx, id, payment.HoldCaptured); err != nil {
+			return err
+		}
+		if err := checkSpending(ctx, tx, h.From, h.Currency, amount); err != nil {
+			return err
+		}
+		if err := checkFunds(ctx, tx, h.From, h.Currency, amount); err != nil {
+			return err
+		}
+
+		t = &payment.Transaction{
+			Kind:       payment.KindCapture,
+			Status:     payment.StatusCompleted,
+			From:       h.From,
+			To:         h.To,
+			Amount:     amount,
+			Currency:   h.Currency,
+			ToAmount:   amount,
+			ToCurrency: h.Currency,
+			Rate:       money.OneRate,
+			Date:       time.Now().UTC(),
+		}
+		if err := insertTransaction(ctx, tx, t); err != nil {
+			return err
+		}
+		if _, err := tx.Update(tableHold).Set(goqu.Record{"transaction_id": t.ID}).
+			Where(goqu.I("id").Eq(id)).Executor().ExecContext(ctx); err != nil {
+			return errors.Wrap(err, "unable to update hold")
+		}
+		return post(ctx, tx, &t.ID, t.Date, transferPostings(t)...)
+	})
+	return t, err
+}
+
+// Void - release hold without moving funds
+func (repo *repository) Void(ctx context.Context, id int64) (*payment.Hold, error) {
+	var hold *payment.Hold
+	err := repo.gq.WithTx(func(tx *goqu.TxDatabase) error {
+		h, err := getActiveHold(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err := setHoldStatus(ctx, tx, id, payment.HoldVoided); err != nil {
+			return err
+		}
+		h.Status = payment.HoldVoided
+		hold = h.toHold()
+		return nil
+	})
+	return hold, err
+}
+
+// ExpireHolds - mark active holds past their TTL as expired, they're already excluded from held amount
+// and can't be captured, so it only keeps active holds index small
+func (repo *repository) ExpireHolds(ctx context.Context) (int64, error) {
+	res, err := repo.gq.Update(tableHold).Set(goqu.Record{"status": payment.HoldExpired}).
+		Where(goqu.I("status").Eq(payment.HoldActive), goqu.I("expires_at").Lte(time.Now().UTC())).Executor().ExecContext(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to expire holds")
+	}
+	return res.RowsAffected()
+}
+
+func setHoldStatus(ctx context.Context, tx *goqu.TxDatabase, id int64, status payment.HoldStatus) error {
+	_, err := tx.Update(tableHold).Set(goqu.Record{"status": status}).Where(goqu.I("id").Eq(id)).Executor().ExecContext(ctx)
+	return errors.Wrap(err, "unable to update hold status")
+}
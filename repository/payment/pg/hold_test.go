@@ -0,0 +1,164 @@
+package pg
+
+import (
+	"coins/pkg/money"
+	"coins/pkg/payment"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// expectLock expect lock of account 1 and status of accounts 1 and 2
+func expectLock(mock sqlmock.Sqlmock) {
+	mock.ExpectBegin()
+	mock.ExpectExec(`SET LOCAL lock_timeout`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`FROM "account"`).WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(1, "active").AddRow(2, "active"))
+}
+
+// expectOutflow expect sums of outflow and active holds of account
+func expectOutflow(mock sqlmock.Sqlmock, outflow, held string) {
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\("amount"\), 0\) FROM "transaction"`).WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(outflow))
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\("amount"\), 0\) FROM "hold"`).WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(held))
+}
+
+// expectLimits expect spending limits of account wallet with daily outflow cap, empty cap isn't set
+func expectLimits(mock sqlmock.Sqlmock, dailyOutflow string) {
+	rows := sqlmock.NewRows([]string{"daily_outflow"})
+	if dailyOutflow != "" {
+		rows.AddRow(dailyOutflow)
+	}
+	mock.ExpectQuery(`FROM "spending_limit"`).WillReturnRows(rows)
+}
+
+// expectHold expect the funds check and insert of hold which is placed
+func expectHold(mock sqlmock.Sqlmock, dailyOutflow string) {
+	mock.ExpectQuery(`FROM "balance"`).WillReturnRows(sqlmock.NewRows([]string{"account_id", "currency", "balance"}).AddRow(1, "EUR", "1000.00"))
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\("amount"\), 0\) FROM "hold"`).WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow("0"))
+	expectLimits(mock, dailyOutflow)
+	mock.ExpectQuery(`INSERT INTO "hold"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+}
+
+func newHold() *payment.Hold {
+	return &payment.Hold{From: 1, To: 2, Amount: 6000, Currency: "EUR", Status: payment.HoldActive, ExpiresAt: time.Now().Add(time.Hour)}
+}
+
+func TestAuthorizeCountsActiveHoldsInDailyOutflow(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repo := NewRepository(db)
+	ctx := payment.WithVelocityLimit(context.Background(), payment.VelocityLimit{
+		DailyOutflow: map[money.Currency]money.Amount{"EUR": 10000},
+	})
+
+	// the first hold is under the cap
+	expectLock(mock)
+	expectOutflow(mock, "0", "0")
+	expectLimits(mock, "")
+	expectHold(mock, "")
+	if _, err := repo.Authorize(ctx, newHold()); err != nil {
+		t.Fatalf("first hold: %v", err)
+	}
+
+	// the second hold is under the cap too, but together with the first one it's over
+	expectLock(mock)
+	expectOutflow(mock, "0", "60.00")
+	mock.ExpectRollback()
+	want := payment.ErrLimitExceeded{ID: 1, Limit: payment.LimitDailyOutflow, Max: "100.00 EUR"}
+	if _, err := repo.Authorize(ctx, newHold()); err != want {
+		t.Fatalf("second hold: got %v, want %v", err, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuthorizeChecksSpendingLimits(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	// the first hold is under the daily outflow cap of the wallet
+	expectLock(mock)
+	expectLimits(mock, "100.00")
+	expectOutflow(mock, "0", "0")
+	expectHold(mock, "100.00")
+	if _, err := repo.Authorize(ctx, newHold()); err != nil {
+		t.Fatalf("first hold: %v", err)
+	}
+
+	// the second hold breaks the cap together with the first one
+	expectLock(mock)
+	expectLimits(mock, "100.00")
+	expectOutflow(mock, "0", "60.00")
+	mock.ExpectRollback()
+	want := payment.ErrLimitExceeded{ID: 1, Limit: payment.LimitDailyOutflowCap, Max: "100.00 EUR"}
+	if _, err := repo.Authorize(ctx, newHold()); err != want {
+		t.Fatalf("second hold: got %v, want %v", err, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecordHoldStatus(t *testing.T) {
+	now := time.Now().UTC()
+	tests := []struct {
+		status    payment.HoldStatus
+		expiresAt time.Time
+		want      payment.HoldStatus
+	}{
+		{payment.HoldActive, now.Add(time.Minute), payment.HoldActive},
+		{payment.HoldActive, now.Add(-time.Minute), payment.HoldExpired},
+		{payment.HoldCaptured, now.Add(-time.Minute), payment.HoldCaptured},
+		{payment.HoldVoided, now.Add(time.Minute), payment.HoldVoided},
+	}
+	for _, tt := range tests {
+		r := &recordHold{ID: 1, Status: tt.status, ExpiresAt: tt.expiresAt}
+		if got := r.toHold().Status; got != tt.want {
+			t.Errorf("%s expiring at %s: got %s, want %s", tt.status, tt.expiresAt, got, tt.want)
+		}
+	}
+}
+
+func TestCaptureChecksHold(t *testing.T) {
+	columns := []string{"id", "from", "to", "amount", "currency", "status", "expires_at"}
+	later, earlier := time.Now().Add(time.Hour), time.Now().Add(-time.Hour)
+	tests := []struct {
+		name   string
+		rows   *sqlmock.Rows
+		amount money.Amount
+		want   error
+	}{
+		{"not found", sqlmock.NewRows(columns), 0, payment.ErrHoldNotFound{ID: 1}},
+		{"expired", sqlmock.NewRows(columns).AddRow(1, 1, 2, "10.00", "EUR", "active", earlier), 0, payment.ErrHoldExpired{ID: 1}},
+		{"voided", sqlmock.NewRows(columns).AddRow(1, 1, 2, "10.00", "EUR", "voided", later), 0, payment.ErrHoldNotActive{ID: 1, Status: payment.HoldVoided}},
+		{"exceeds hold", sqlmock.NewRows(columns).AddRow(1, 1, 2, "10.00", "EUR", "active", later), 1001, payment.ErrCaptureExceedsHold{ID: 1, Amount: 1000}},
+	}
+	for _, tt := range tests {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM "hold" WHERE \("id" = 1\).* FOR UPDATE`).WillReturnRows(tt.rows)
+		mock.ExpectRollback()
+		if _, err := NewRepository(db).Capture(context.Background(), 1, tt.amount); err != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		db.Close()
+	}
+}
@@ -0,0 +1,81 @@
+package pg
+
+import (
+	"coins/pkg/payment"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/doug-martin/goqu/v8"
+	"github.com/pkg/errors"
+)
+
+const tableIdempotencyKey = "idempotency_key"
+
+type recordIdempotencyKey struct {
+	Key         string    `db:"key"`
+	Operation   string    `db:"operation"`
+	Owner       string    `db:"owner"`
+	RequestHash string    `db:"request_hash"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// idempotent run fn inside tx only once per idempotency key carried by ctx. Result of the first run
+// stored alongside the key and decoded into `out` when the same request is repeated
+func (repo *repository) idempotent(ctx context.Context, tx *goqu.TxDatabase, out interface{}, fn func() error) error {
+	k, ok := payment.IdempotencyKeyFromContext(ctx)
+	if !ok {
+		return fn()
+	}
+
+	// a new key is inserted, expired one is taken over, a live one is left untouched and nothing returned.
+	// Concurrent request with the same key waits here until the first one commits or rolls back
+	now := time.Now().UTC()
+	r := &recordIdempotencyKey{Key: k.Key, Operation: k.Operation, Owner: k.Owner, RequestHash: k.RequestHash, CreatedAt: now}
+	conflict := goqu.DoUpdate("key, operation, owner", goqu.Record{
+		"request_hash": goqu.L("EXCLUDED.request_hash"),
+		"created_at":   goqu.L("EXCLUDED.created_at"),
+		"response":     nil,
+	}).Where(goqu.I(tableIdempotencyKey + ".created_at").Lt(now.Add(-repo.idempotencyRetention)))
+	var key string
+	inserted, err := tx.Insert(tableIdempotencyKey).Rows(r).OnConflict(conflict).Returning(goqu.C("key")).Executor().ScanValContext(ctx, &key)
+	if err != nil {
+		return errors.Wrap(err, "unable to store idempotency key")
+	}
+
+	if !inserted {
+		var stored struct {
+			RequestHash string `db:"request_hash"`
+			Response    []byte `db:"response"`
+		}
+		if _, err := tx.From(tableIdempotencyKey).Select("request_hash", "response").
+			Where(goqu.Ex{"key": k.Key, "operation": k.Operation, "owner": k.Owner}).ScanStructContext(ctx, &stored); err != nil {
+			return errors.Wrap(err, "unable to get idempotency key")
+		}
+		if stored.RequestHash != k.RequestHash {
+			return payment.ErrIdempotencyConflict{Key: k.Key}
+		}
+		return json.Unmarshal(stored.Response, out)
+	}
+
+	if err := fn(); err != nil {
+		return err
+	}
+	response, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Update(tableIdempotencyKey).Set(goqu.Record{"response": string(response)}).
+		Where(goqu.Ex{"key": k.Key, "operation": k.Operation, "owner": k.Owner}).Executor().ExecContext(ctx)
+	return errors.Wrap(err, "unable to store idempotent response")
+}
+
+// PurgeIdempotencyKeys delete keys older than retention window
+func (repo *repository) PurgeIdempotencyKeys(ctx context.Context) (int64, error) {
+	res, err := repo.gq.Delete(tableIdempotencyKey).
+		Where(goqu.I("created_at").Lt(time.Now().UTC().Add(-repo.idempotencyRetention))).Executor().ExecContext(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to purge idempotency keys")
+	}
+	return res.RowsAffected()
+}
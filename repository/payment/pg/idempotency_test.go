@@ -0,0 +1,114 @@
+package pg
+
+import (
+	"coins/pkg/payment"
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/doug-martin/goqu/v8"
+)
+
+func TestIdempotent(t *testing.T) {
+	key := payment.IdempotencyKey{Key: "k1", Operation: payment.OperationTransfer, Owner: "key:1", RequestHash: "h1"}
+	tests := []struct {
+		name string
+		key  payment.IdempotencyKey
+		// expect queries after the key insert
+		expect func(sqlmock.Sqlmock)
+		// ran is true when the operation runs
+		ran  bool
+		want payment.Transaction
+		err  error
+	}{
+		{
+			name: "new key",
+			key:  key,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO "idempotency_key"`).WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("k1"))
+				mock.ExpectExec(`UPDATE "idempotency_key" SET "response"='{"id":7,`).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			ran:  true,
+			want: payment.Transaction{ID: 7},
+		},
+		{
+			name: "replay",
+			key:  key,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO "idempotency_key"`).WillReturnRows(sqlmock.NewRows([]string{"key"}))
+				mock.ExpectQuery(`SELECT "request_hash", "response" FROM "idempotency_key"`).
+					WillReturnRows(sqlmock.NewRows([]string{"request_hash", "response"}).AddRow("h1", []byte(`{"id":3}`)))
+				mock.ExpectCommit()
+			},
+			want: payment.Transaction{ID: 3},
+		},
+		{
+			name: "other request with the same key",
+			key:  payment.IdempotencyKey{Key: "k1", Operation: payment.OperationTransfer, Owner: "key:1", RequestHash: "h2"},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO "idempotency_key"`).WillReturnRows(sqlmock.NewRows([]string{"key"}))
+				mock.ExpectQuery(`SELECT "request_hash", "response" FROM "idempotency_key"`).
+					WillReturnRows(sqlmock.NewRows([]string{"request_hash", "response"}).AddRow("h1", []byte(`{"id":3}`)))
+				mock.ExpectRollback()
+			},
+			err: payment.ErrIdempotencyConflict{Key: "k1"},
+		},
+		{
+			name: "failed operation",
+			key:  key,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO "idempotency_key"`).WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("k1"))
+				mock.ExpectRollback()
+			},
+			ran: true,
+			err: payment.ErrInsufficientFunds{ID: 1},
+		},
+		{
+			name: "without key",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectCommit()
+			},
+			ran:  true,
+			want: payment.Transaction{ID: 7},
+		},
+	}
+	for _, tt := range tests {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		mock.ExpectBegin()
+		tt.expect(mock)
+		repo := NewRepository(db).(*repository)
+		ctx := payment.WithIdempotencyKey(context.Background(), tt.key)
+
+		var (
+			got payment.Transaction
+			ran bool
+		)
+		err = repo.gq.WithTx(func(tx *goqu.TxDatabase) error {
+			return repo.idempotent(ctx, tx, &got, func() error {
+				ran = true
+				if tt.err != nil {
+					return tt.err
+				}
+				got.ID = 7
+				return nil
+			})
+		})
+		if err != tt.err {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, tt.err)
+		}
+		if ran != tt.ran {
+			t.Errorf("%s: got ran %t, want %t", tt.name, ran, tt.ran)
+		}
+		if got.ID != tt.want.ID {
+			t.Errorf("%s: got transaction %d, want %d", tt.name, got.ID, tt.want.ID)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		db.Close()
+	}
+}
@@ -0,0 +1,124 @@
+package pg
+
+import (
+	"coins/pkg/money"
+	"coins/pkg/payment"
+	"context"
+	"time"
+
+	"github.com/doug-martin/goqu/v8"
+	"github.com/pkg/errors"
+)
+
+const tablePosting = "posting"
+
+type recordPosting struct {
+	ID            int64          `db:"id" goqu:"skipinsert,skipupdate"`
+	TransactionID *int64         `db:"transaction_id"`
+	AccountID     int64          `db:"account_id"`
+	Currency      money.Currency `db:"currency"`
+	Amount        money.Amount   `db:"amount"`
+	Date          time.Time      `db:"date"`
+}
+
+// post write balanced postings of one transaction into the journal and apply them to cached balances
+// of non-system accounts. Postings which don't sum to zero in every currency are rejected
+func post(ctx context.Context, tx *goqu.TxDatabase, transactionID *int64, date time.Time, postings ...payment.Posting) error {
+	sum := make(map[money.Currency]money.Amount)
+	for _, p := range postings {
+		s, err := sum[p.Currency].Add(p.Amount)
+		if err != nil {
+			return err
+		}
+		sum[p.Currency] = s
+	}
+	for currency, s := range sum {
+		if s != 0 {
+			return errors.Errorf("unbalanced postings, %s sum to %s", currency, s)
+		}
+	}
+
+	rr := make([]*recordPosting, 0, len(postings))
+	for _, p := range postings {
+		rr = append(rr, &recordPosting{
+			TransactionID: transactionID,
+			AccountID:     p.AccountID,
+			Currency:      p.Currency,
+			Amount:        p.Amount,
+			Date:          date,
+		})
+	}
+	if _, err := tx.Insert(tablePosting).Rows(rr).Executor().ExecContext(ctx); err != nil {
+		return errors.Wrap(err, "unable to store postings")
+	}
+
+	for _, p := range postings {
+		if payment.IsSystemAccount(p.AccountID) {
+			continue
+		}
+		if err := addBalance(ctx, tx, p.AccountID, p.Currency, p.Amount); err != nil {
+			if _, ok := err.(money.ErrOverflow); ok {
+				return err
+			}
+			return errors.Wrap(err, "unable to update balance")
+		}
+	}
+	return nil
+}
+
+// addBalance add amount to cached balance of account wallet, raise ErrOverflow when the balance would leave
+// the range of Amount, NUMERIC column is wider. Balance of account must be locked
+func addBalance(ctx context.Context, tx *goqu.TxDatabase, accountID int64, currency money.Currency, amount money.Amount) error {
+	current, err := getBalance(ctx, tx, accountID, currency)
+	if err != nil {
+		return err
+	}
+	if _, err := current.Balance.Add(amount); err != nil {
+		return err
+	}
+	b := &recordBalance{AccountID: accountID, Currency: currency, Balance: amount}
+	_, err = tx.Insert(tableBalance).Rows(b).
+		OnConflict(goqu.DoUpdate("account_id, currency", goqu.Record{"balance": goqu.L("balance.balance + EXCLUDED.balance")})).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+// VerifyBalance compare cached balances of account with sum of its postings
+func (repo *repository) VerifyBalance(ctx context.Context, accountID int64) ([]*payment.BalanceCheck, error) {
+	var rr []struct {
+		Currency money.Currency `db:"currency"`
+		Cached   money.Amount   `db:"cached"`
+		Ledger   money.Amount   `db:"ledger"`
+	}
+	ledger := repo.gq.From(tablePosting).
+		Select(goqu.C("currency"), goqu.SUM("amount").As("amount")).
+		Where(goqu.I("account_id").Eq(accountID)).
+		GroupBy("currency")
+	cached := repo.gq.From(tableBalance).
+		Select("currency", "balance").
+		Where(goqu.I("account_id").Eq(accountID))
+	err := repo.gq.From(cached.As("b")).
+		FullOuterJoin(ledger.As("l"), goqu.On(goqu.I("b.currency").Eq(goqu.I("l.currency")))).
+		Select(
+			goqu.COALESCE(goqu.I("b.currency"), goqu.I("l.currency")).As("currency"),
+			goqu.COALESCE(goqu.I("b.balance"), 0).As("cached"),
+			goqu.COALESCE(goqu.I("l.amount"), 0).As("ledger"),
+		).
+		Order(goqu.C("currency").Asc()).
+		ScanStructsContext(ctx, &rr)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to verify balance")
+	}
+
+	cc := make([]*payment.BalanceCheck, 0, len(rr))
+	for _, r := range rr {
+		cc = append(cc, &payment.BalanceCheck{
+			AccountID:  accountID,
+			Currency:   r.Currency,
+			Cached:     r.Cached,
+			Ledger:     r.Ledger,
+			Consistent: r.Cached == r.Ledger,
+		})
+	}
+	return cc, nil
+}
@@ -0,0 +1,159 @@
+package pg
+
+import (
+	"coins/pkg/money"
+	"coins/pkg/payment"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/doug-martin/goqu/v8"
+)
+
+func TestTransferPostings(t *testing.T) {
+	tests := []struct {
+		name string
+		t    payment.Transaction
+		want []payment.Posting
+	}{
+		{
+			name: "same currency",
+			t:    payment.Transaction{From: 1, To: 2, Amount: 1000, Currency: "EUR", ToAmount: 1000, ToCurrency: "EUR"},
+			want: []payment.Posting{
+				{AccountID: 1, Currency: "EUR", Amount: -1000},
+				{AccountID: 2, Currency: "EUR", Amount: 1000},
+			},
+		},
+		{
+			name: "cross-currency",
+			t:    payment.Transaction{From: 1, To: 2, Amount: 1000, Currency: "EUR", ToAmount: 1100, ToCurrency: "USD"},
+			want: []payment.Posting{
+				{AccountID: 1, Currency: "EUR", Amount: -1000},
+				{AccountID: payment.SystemExchange, Currency: "EUR", Amount: 1000},
+				{AccountID: payment.SystemExchange, Currency: "USD", Amount: -1100},
+				{AccountID: 2, Currency: "USD", Amount: 1100},
+			},
+		},
+	}
+	for _, tt := range tests {
+		got := transferPostings(&tt.t)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+		sum := make(map[money.Currency]money.Amount)
+		for _, p := range got {
+			sum[p.Currency] += p.Amount
+		}
+		for currency, s := range sum {
+			if s != 0 {
+				t.Errorf("%s: %s postings sum to %s", tt.name, currency, s)
+			}
+		}
+	}
+}
+
+func TestPost(t *testing.T) {
+	id := int64(7)
+	tests := []struct {
+		name     string
+		postings []payment.Posting
+		expect   func(sqlmock.Sqlmock)
+		err      bool
+	}{
+		{
+			name: "unbalanced",
+			postings: []payment.Posting{
+				{AccountID: 1, Currency: "EUR", Amount: -1000},
+				{AccountID: 2, Currency: "EUR", Amount: 999},
+			},
+			expect: func(mock sqlmock.Sqlmock) {},
+			err:    true,
+		},
+		{
+			name: "balanced in one currency only",
+			postings: []payment.Posting{
+				{AccountID: 1, Currency: "EUR", Amount: -1000},
+				{AccountID: 2, Currency: "USD", Amount: 1000},
+			},
+			expect: func(mock sqlmock.Sqlmock) {},
+			err:    true,
+		},
+		{
+			name: "top-up from system account",
+			postings: []payment.Posting{
+				{AccountID: payment.SystemFunding, Currency: "EUR", Amount: -1000},
+				{AccountID: 1, Currency: "EUR", Amount: 1000},
+			},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`INSERT INTO "posting"`).WillReturnResult(sqlmock.NewResult(0, 2))
+				// cached balance of system account isn't kept
+				mock.ExpectQuery(`FROM "balance"`).WillReturnRows(sqlmock.NewRows([]string{"account_id", "currency", "balance"}).AddRow(1, "EUR", "5.00"))
+				mock.ExpectExec(`INSERT INTO "balance" .* ON CONFLICT \(account_id, currency\) DO UPDATE SET "balance"=balance.balance \+ EXCLUDED.balance`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "balance overflow",
+			postings: []payment.Posting{
+				{AccountID: payment.SystemFunding, Currency: "EUR", Amount: -1000},
+				{AccountID: 1, Currency: "EUR", Amount: 1000},
+			},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`INSERT INTO "posting"`).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectQuery(`FROM "balance"`).WillReturnRows(sqlmock.NewRows([]string{"account_id", "currency", "balance"}).AddRow(1, "EUR", "92233720368547758.00"))
+			},
+			err: true,
+		},
+	}
+	for _, tt := range tests {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		mock.ExpectBegin()
+		tt.expect(mock)
+		if tt.err {
+			mock.ExpectRollback()
+		} else {
+			mock.ExpectCommit()
+		}
+		err = goqu.New("postgres", db).WithTx(func(tx *goqu.TxDatabase) error {
+			return post(context.Background(), tx, &id, time.Now().UTC(), tt.postings...)
+		})
+		if (err != nil) != tt.err {
+			t.Errorf("%s: got error %v, want error %t", tt.name, err, tt.err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		db.Close()
+	}
+}
+
+func TestVerifyBalance(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`FULL OUTER JOIN`).WillReturnRows(sqlmock.NewRows([]string{"currency", "cached", "ledger"}).
+		AddRow("EUR", "10.00", "10.00").
+		AddRow("USD", "5.00", "4.00"))
+
+	got, err := NewRepository(db).VerifyBalance(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*payment.BalanceCheck{
+		{AccountID: 1, Currency: "EUR", Cached: 1000, Ledger: 1000, Consistent: true},
+		{AccountID: 1, Currency: "USD", Cached: 500, Ledger: 400, Consistent: false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,82 @@
+package pg
+
+import (
+	"coins/pkg/money"
+	"coins/pkg/payment"
+	"context"
+	"time"
+
+	"github.com/doug-martin/goqu/v8"
+	"github.com/pkg/errors"
+)
+
+const tableLimits = "spending_limit"
+
+// recordLimits spending limits of account wallet, managed by account service
+type recordLimits struct {
+	Overdraft      *money.Amount `db:"overdraft"`
+	MinBalance     *money.Amount `db:"min_balance"`
+	MaxTransaction *money.Amount `db:"max_transaction"`
+	DailyOutflow   *money.Amount `db:"daily_outflow"`
+	MonthlyOutflow *money.Amount `db:"monthly_outflow"`
+}
+
+// floor return the lowest available balance wallet may reach, zero without overdraft and minimum balance
+func (r *recordLimits) floor() money.Amount {
+	switch {
+	case r.Overdraft != nil:
+		return -*r.Overdraft
+	case r.MinBalance != nil:
+		return *r.MinBalance
+	}
+	return 0
+}
+
+// getLimits return spending limits of account wallet, without limits every setting is nil
+func getLimits(ctx context.Context, tx *goqu.TxDatabase, accountID int64, currency money.Currency) (*recordLimits, error) {
+	r := &recordLimits{}
+	_, err := tx.From(tableLimits).Where(goqu.Ex{"account_id": accountID, "currency": currency}).ScanStructContext(ctx, r)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get spending limits")
+	}
+	return r, nil
+}
+
+// checkSpending raise ErrLimitExceeded when debit or hold of amount breaks single transaction, daily or monthly outflow
+// cap of account wallet, active holds count towards the caps. Balance of account must be locked, so concurrent debits
+// can't pass the caps together
+func checkSpending(ctx context.Context, tx *goqu.TxDatabase, accountID int64, currency money.Currency, amount money.Amount) error {
+	l, err := getLimits(ctx, tx, accountID, currency)
+	if err != nil {
+		return err
+	}
+	exceeded := func(limit payment.Limit, max money.Amount) error {
+		return payment.ErrLimitExceeded{ID: accountID, Limit: limit, Max: max.String() + " " + string(currency)}
+	}
+	if l.MaxTransaction != nil && amount > *l.MaxTransaction {
+		return exceeded(payment.LimitMaxTransaction, *l.MaxTransaction)
+	}
+
+	now := time.Now().UTC()
+	caps := []struct {
+		limit payment.Limit
+		max   *money.Amount
+		since time.Time
+	}{
+		{payment.LimitDailyOutflowCap, l.DailyOutflow, now.Add(-dailyWindow)},
+		{payment.LimitMonthlyOutflowCap, l.MonthlyOutflow, now.Add(-monthlyWindow)},
+	}
+	for _, c := range caps {
+		if c.max == nil {
+			continue
+		}
+		sum, err := sumCommittedOutflow(ctx, tx, accountID, currency, c.since)
+		if err != nil {
+			return err
+		}
+		if total, err := sum.Add(amount); err != nil || total > *c.max {
+			return exceeded(c.limit, *c.max)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package pg
+
+import (
+	"coins/pkg/payment"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/doug-martin/goqu/v8"
+	"github.com/lib/pq"
+)
+
+func TestLockBalance(t *testing.T) {
+	tests := []struct {
+		name     string
+		deadline time.Duration
+		// timeout is the expected lock_timeout, empty when lock isn't requested
+		timeout string
+		lockErr error
+		busy    bool
+	}{
+		{"default wait", 0, `SET LOCAL lock_timeout = 5000$`, nil, false},
+		{"wait until deadline", time.Minute, `SET LOCAL lock_timeout = 59\d{3}$`, nil, false},
+		{"deadline passed", -time.Second, "", nil, true},
+		{"lock timeout", 0, `SET LOCAL lock_timeout = 5000$`, &pq.Error{Code: lockNotAvailable}, true},
+		{"statement canceled", 0, `SET LOCAL lock_timeout = 5000$`, &pq.Error{Code: queryCanceled}, true},
+		{"other error", 0, `SET LOCAL lock_timeout = 5000$`, errors.New("connection reset"), false},
+	}
+	for _, tt := range tests {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		mock.ExpectBegin()
+		if tt.timeout != "" {
+			mock.ExpectExec(tt.timeout).WillReturnResult(sqlmock.NewResult(0, 0))
+			lock := mock.ExpectExec(`SELECT pg_advisory_xact_lock\(\$1\)`).WithArgs(1)
+			if tt.lockErr != nil {
+				lock.WillReturnError(tt.lockErr)
+			} else {
+				lock.WillReturnResult(sqlmock.NewResult(0, 0))
+			}
+		}
+		mock.ExpectRollback()
+
+		ctx, cancel := context.Background(), context.CancelFunc(func() {})
+		if tt.deadline != 0 {
+			ctx, cancel = context.WithTimeout(ctx, tt.deadline)
+		}
+		gq := goqu.New("postgres", db)
+		err = gq.WithTx(func(tx *goqu.TxDatabase) error {
+			if err := lockBalance(ctx, tx, 1); err != nil {
+				return err
+			}
+			return errors.New("rollback")
+		})
+		cancel()
+
+		_, busy := err.(payment.ErrAccountBusy)
+		if busy != tt.busy {
+			t.Errorf("%s: got error %v, want busy %t", tt.name, err, tt.busy)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		db.Close()
+	}
+}
+
+func TestLockBalancesOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	// accounts are locked once in ascending order, so concurrent transfers can't deadlock, system accounts aren't locked
+	for _, id := range []int64{2, 3} {
+		mock.ExpectExec(`SET LOCAL lock_timeout`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`SELECT pg_advisory_xact_lock`).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	mock.ExpectCommit()
+	err = goqu.New("postgres", db).WithTx(func(tx *goqu.TxDatabase) error {
+		return lockBalances(context.Background(), tx, 3, payment.SystemFunding, 2, 3)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
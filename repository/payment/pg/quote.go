@@ -0,0 +1,94 @@
+package pg
+
+import (
+	"coins/pkg/money"
+	"coins/pkg/payment"
+	"context"
+	"time"
+
+	"github.com/doug-martin/goqu/v8"
+	"github.com/pkg/errors"
+)
+
+const tableQuote = "quote"
+
+type recordQuote struct {
+	ID         int64          `db:"id" goqu:"skipinsert,skipupdate"`
+	Currency   money.Currency `db:"currency"`
+	ToCurrency money.Currency `db:"to_currency"`
+	Amount     money.Amount   `db:"amount"`
+	ToAmount   money.Amount   `db:"to_amount"`
+	Rate       money.Rate     `db:"rate"`
+	ExpiresAt  time.Time      `db:"expires_at"`
+	Owner      string         `db:"owner"`
+	Used       bool           `db:"used"`
+}
+
+func (q *recordQuote) toQuote() *payment.Quote {
+	return &payment.Quote{
+		ID:         q.ID,
+		Currency:   q.Currency,
+		ToCurrency: q.ToCurrency,
+		Amount:     q.Amount,
+		ToAmount:   q.ToAmount,
+		Rate:       q.Rate,
+		ExpiresAt:  q.ExpiresAt,
+		Owner:      q.Owner,
+	}
+}
+
+func fromQuote(q *payment.Quote) *recordQuote {
+	return &recordQuote{
+		ID:         q.ID,
+		Currency:   q.Currency,
+		ToCurrency: q.ToCurrency,
+		Amount:     q.Amount,
+		ToAmount:   q.ToAmount,
+		Rate:       q.Rate,
+		ExpiresAt:  q.ExpiresAt,
+		Owner:      q.Owner,
+	}
+}
+
+func (repo *repository) StoreQuote(ctx context.Context, q *payment.Quote) (*payment.Quote, error) {
+	r := fromQuote(q)
+	res := repo.gq.From(tableQuote).Insert().Returning(goqu.C("id")).Rows(r).Executor()
+	var id int64
+	if _, err := res.ScanValContext(ctx, &id); err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve last inserted ID")
+	}
+	q.ID = id
+	return q, nil
+}
+
+// GetQuote return quote or ErrQuoteNotFound, quote is checked to be unused only when transfer applies it,
+// so retry of idempotent transfer still finds the quote
+func (repo *repository) GetQuote(ctx context.Context, id int64) (*payment.Quote, error) {
+	r := &recordQuote{}
+	found, err := repo.gq.From(tableQuote).Where(goqu.I("id").Eq(id)).ScanStructContext(ctx, r)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get quote")
+	}
+	if !found {
+		return nil, payment.ErrQuoteNotFound{ID: id}
+	}
+	return r.toQuote(), nil
+}
+
+// useQuote mark quote as used so the locked rate can be applied only once, raise ErrQuoteExpired when quote TTL
+// passed. It runs inside idempotent transfer, so retry of transfer made with the quote isn't rejected after TTL
+func useQuote(ctx context.Context, tx *goqu.TxDatabase, id int64) error {
+	var expiresAt time.Time
+	found, err := tx.Update(tableQuote).Set(goqu.Record{"used": true}).Where(goqu.Ex{"id": id, "used": false}).
+		Returning(goqu.C("expires_at")).Executor().ScanValContext(ctx, &expiresAt)
+	if err != nil {
+		return errors.Wrap(err, "unable to use quote")
+	}
+	if !found {
+		return payment.ErrQuoteNotFound{ID: id}
+	}
+	if time.Now().After(expiresAt) {
+		return payment.ErrQuoteExpired{ID: id}
+	}
+	return nil
+}
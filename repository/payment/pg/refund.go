@@ -0,0 +1,81 @@
+package pg
+
+import (
+	"coins/pkg/money"
+	"coins/pkg/payment"
+	"context"
+	"time"
+
+	"github.com/doug-martin/goqu/v8"
+	"github.com/doug-martin/goqu/v8/exp"
+	"github.com/pkg/errors"
+)
+
+// Refund - return `amount`(the whole remaining amount when zero) of transfer or capture from its recipient back
+// to its sender. Original transaction is locked, so concurrent partial refunds can't exceed its amount
+func (repo *repository) Refund(ctx context.Context, originalID int64, amount money.Amount) (*payment.Transaction, error) {
+	t := &payment.Transaction{}
+	err := repo.gq.WithTx(func(tx *goqu.TxDatabase) error {
+		return repo.idempotent(ctx, tx, t, func() error {
+			o := &recordTransaction{}
+			found, err := tx.From(tableTransaction).Where(goqu.I("id").Eq(originalID)).ForUpdate(exp.Wait).ScanStructContext(ctx, o)
+			if err != nil {
+				return errors.Wrap(err, "unable to get transaction")
+			}
+			if !found {
+				return payment.ErrTransactionNotFound{ID: originalID}
+			}
+			if (o.Kind != payment.KindTransfer && o.Kind != payment.KindCapture) || o.Currency != o.ToCurrency {
+				return payment.ErrNotRefundable{ID: originalID}
+			}
+
+			var refunded money.Amount
+			if _, err := tx.From(tableTransaction).Select(goqu.COALESCE(goqu.SUM("amount"), 0)).
+				Where(goqu.Ex{"original_transaction_id": originalID, "kind": payment.KindRefund}).
+				ScanValContext(ctx, &refunded); err != nil {
+				return errors.Wrap(err, "unable to get refunded amount")
+			}
+			remaining := o.Amount - refunded
+			if amount == 0 {
+				amount = remaining
+			}
+			if amount > remaining || remaining == 0 {
+				return payment.ErrRefundExceedsOriginal{ID: originalID, Remaining: remaining}
+			}
+
+			if err := lockBalances(ctx, tx, o.To, o.From); err != nil {
+				return err
+			}
+			if err := checkAccounts(ctx, tx, o.To, o.From); err != nil {
+				return err
+			}
+			// refund returns funds recipient received, so it keeps the balance floor of recipient but isn't
+			// capped by its spending and velocity limits
+			if err := checkFunds(ctx, tx, o.To, o.Currency, amount); err != nil {
+				return err
+			}
+
+			*t = payment.Transaction{
+				Kind:                  payment.KindRefund,
+				Status:                payment.StatusCompleted,
+				From:                  o.To,
+				To:                    o.From,
+				Amount:                amount,
+				Currency:              o.Currency,
+				ToAmount:              amount,
+				ToCurrency:            o.Currency,
+				Rate:                  money.OneRate,
+				OriginalTransactionID: originalID,
+				Date:                  time.Now().UTC(),
+			}
+			if err := insertTransaction(ctx, tx, t); err != nil {
+				return err
+			}
+			return post(ctx, tx, &t.ID, t.Date, transferPostings(t)...)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
@@ -0,0 +1,47 @@
+package pg
+
+import (
+	"coins/pkg/money"
+	"coins/pkg/payment"
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestRefundChecksOriginal(t *testing.T) {
+	columns := []string{"id", "kind", "from", "to", "amount", "currency", "to_amount", "to_currency"}
+	tests := []struct {
+		name     string
+		original *sqlmock.Rows
+		refunded string
+		amount   money.Amount
+		want     error
+	}{
+		{"not found", sqlmock.NewRows(columns), "", 0, payment.ErrTransactionNotFound{ID: 1}},
+		{"top-up", sqlmock.NewRows(columns).AddRow(1, "topup", -1, 2, "10.00", "EUR", "10.00", "EUR"), "", 0, payment.ErrNotRefundable{ID: 1}},
+		{"cross-currency", sqlmock.NewRows(columns).AddRow(1, "transfer", 2, 3, "10.00", "EUR", "11.00", "USD"), "", 0, payment.ErrNotRefundable{ID: 1}},
+		{"over remaining", sqlmock.NewRows(columns).AddRow(1, "transfer", 2, 3, "10.00", "EUR", "10.00", "EUR"), "4.00", 601, payment.ErrRefundExceedsOriginal{ID: 1, Remaining: 600}},
+		{"fully refunded", sqlmock.NewRows(columns).AddRow(1, "capture", 2, 3, "10.00", "EUR", "10.00", "EUR"), "10.00", 0, payment.ErrRefundExceedsOriginal{ID: 1, Remaining: 0}},
+	}
+	for _, tt := range tests {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM "transaction" WHERE \("id" = 1\).* FOR UPDATE`).WillReturnRows(tt.original)
+		if tt.refunded != "" {
+			mock.ExpectQuery(`SELECT COALESCE\(SUM\("amount"\), 0\) FROM "transaction" WHERE \(\("kind" = 'refund'\) AND \("original_transaction_id" = 1\)\)`).
+				WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(tt.refunded))
+		}
+		mock.ExpectRollback()
+		if _, err := NewRepository(db).Refund(context.Background(), 1, tt.amount); err != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		db.Close()
+	}
+}
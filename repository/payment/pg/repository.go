@@ -1,14 +1,19 @@
 package pg
 
 import (
+	"coins/pkg/metadata"
+	"coins/pkg/money"
 	"coins/pkg/payment"
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/doug-martin/goqu/v8"
 	_ "github.com/doug-martin/goqu/v8/dialect/postgres"
+	"github.com/doug-martin/goqu/v8/exp"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 )
 
@@ -18,115 +23,276 @@ const (
 )
 
 type recordBalance struct {
-	AccountID int64   `db:"account_id"`
-	Balance   float64 `db:"balance"`
+	AccountID int64          `db:"account_id"`
+	Currency  money.Currency `db:"currency"`
+	Balance   money.Amount   `db:"balance"`
 }
 
-func (b *recordBalance) toBalance() *payment.Balance {
+func (b *recordBalance) toBalance(held money.Amount) *payment.Balance {
 	return &payment.Balance{
 		AccountID: b.AccountID,
-		Balance:   b.Balance,
-	}
-}
-
-func fromBalance(b *payment.Balance) *recordBalance {
-	return &recordBalance{
-		AccountID: b.AccountID,
-		Balance:   b.Balance,
+		Currency:  b.Currency,
+		Ledger:    b.Balance,
+		Available: b.Balance - held,
 	}
 }
 
 type recordTransaction struct {
-	ID     int64     `db:"id" goqu:"skipinsert,skipupdate"`
-	From   int64     `db:"from"`
-	To     int64     `db:"to"`
-	Amount float64   `db:"amount"`
-	Date   time.Time `db:"date"`
+	ID         int64             `db:"id" goqu:"skipinsert,skipupdate"`
+	Kind       payment.Kind      `db:"kind"`
+	Status     payment.Status    `db:"status"`
+	From       int64             `db:"from"`
+	To         int64             `db:"to"`
+	Amount     money.Amount      `db:"amount"`
+	Currency   money.Currency    `db:"currency"`
+	ToAmount   money.Amount      `db:"to_amount"`
+	ToCurrency money.Currency    `db:"to_currency"`
+	Rate       money.Rate        `db:"rate"`
+	QuoteID    *int64            `db:"quote_id"`
+	OriginalID *int64            `db:"original_transaction_id"`
+	Internal   bool              `db:"internal"`
+	ExternalID *string           `db:"external_id"`
+	Metadata   metadata.Metadata `db:"metadata"`
+	Date       time.Time         `db:"date"`
 }
 
 func (t *recordTransaction) toTransaction() *payment.Transaction {
-	return &payment.Transaction{
-		ID:     t.ID,
-		From:   t.From,
-		To:     t.To,
-		Amount: t.Amount,
-		Date:   t.Date,
+	tr := &payment.Transaction{
+		ID:         t.ID,
+		Kind:       t.Kind,
+		Status:     t.Status,
+		From:       t.From,
+		To:         t.To,
+		Amount:     t.Amount,
+		Currency:   t.Currency,
+		ToAmount:   t.ToAmount,
+		ToCurrency: t.ToCurrency,
+		Rate:       t.Rate,
+		Internal:   t.Internal,
+		Metadata:   t.Metadata,
+		Date:       t.Date,
 	}
+	if t.QuoteID != nil {
+		tr.QuoteID = *t.QuoteID
+	}
+	if t.OriginalID != nil {
+		tr.OriginalTransactionID = *t.OriginalID
+	}
+	if t.ExternalID != nil {
+		tr.ExternalID = *t.ExternalID
+	}
+	return tr
 }
 
 func fromTransaction(t *payment.Transaction) *recordTransaction {
-	return &recordTransaction{
-		ID:     t.ID,
-		From:   t.From,
-		To:     t.To,
-		Amount: t.Amount,
-		Date:   t.Date,
+	r := &recordTransaction{
+		ID:         t.ID,
+		Kind:       t.Kind,
+		Status:     t.Status,
+		From:       t.From,
+		To:         t.To,
+		Amount:     t.Amount,
+		Currency:   t.Currency,
+		ToAmount:   t.ToAmount,
+		ToCurrency: t.ToCurrency,
+		Rate:       t.Rate,
+		Internal:   t.Internal,
+		Metadata:   t.Metadata,
+		Date:       t.Date,
+	}
+	if t.QuoteID != 0 {
+		r.QuoteID = &t.QuoteID
 	}
+	if t.OriginalTransactionID != 0 {
+		r.OriginalID = &t.OriginalTransactionID
+	}
+	if t.ExternalID != "" {
+		r.ExternalID = &t.ExternalID
+	}
+	return r
 }
 
+const (
+	defaultIdempotencyRetention = 24 * time.Hour
+	defaultLockWait             = 5 * time.Second
+)
+
+// postgres error codes
+const (
+	lockNotAvailable = "55P03"
+	queryCanceled    = "57014"
+	uniqueViolation  = "23505"
+)
+
 type repository struct {
-	gq *goqu.Database
+	gq                   *goqu.Database
+	idempotencyRetention time.Duration
 }
 
-// NewRepository - build new repository
-func NewRepository(db *sql.DB) payment.Repository {
-	return &repository{gq: goqu.New("postgres", db)}
+// Option - repository option
+type Option func(*repository)
+
+// WithIdempotencyRetention - set how long idempotency keys are kept, 24h by default
+func WithIdempotencyRetention(d time.Duration) Option {
+	return func(repo *repository) {
+		repo.idempotencyRetention = d
+	}
 }
 
-func (repo *repository) GetBalance(ctx context.Context, id int64) (*payment.Balance, error) {
-	r := &recordBalance{}
-	tx, err := repo.gq.Begin()
-	if err != nil {
-		return nil, err
+// NewRepository - build new repository
+func NewRepository(db *sql.DB, opts ...Option) payment.Repository {
+	repo := &repository{
+		gq:                   goqu.New("postgres", db),
+		idempotencyRetention: defaultIdempotencyRetention,
 	}
-	r, err = getBalance(ctx, tx, id)
-	if err != nil {
+	for _, opt := range opts {
+		opt(repo)
+	}
+	return repo
+}
+
+func (repo *repository) GetBalance(ctx context.Context, id int64) ([]*payment.Balance, error) {
+	var rr []*recordBalance
+	if err := repo.gq.From(tableBalance).Where(goqu.I("account_id").Eq(id)).Order(goqu.I("currency").Asc()).ScanStructsContext(ctx, &rr); err != nil {
 		return nil, errors.Wrap(err, "unable to get balance")
 	}
-	if err = tx.Commit(); err != nil {
-		return nil, err
+	var held []struct {
+		Currency money.Currency `db:"currency"`
+		Amount   money.Amount   `db:"amount"`
+	}
+	if err := repo.gq.From(tableHold).Select(goqu.C("currency"), goqu.SUM("amount").As("amount")).
+		Where(activeHolds(id)).GroupBy("currency").ScanStructsContext(ctx, &held); err != nil {
+		return nil, errors.Wrap(err, "unable to get held amount")
+	}
+	heldByCurrency := make(map[money.Currency]money.Amount, len(held))
+	for _, h := range held {
+		heldByCurrency[h.Currency] = h.Amount
+	}
+
+	bb := make([]*payment.Balance, 0, len(rr))
+	for _, r := range rr {
+		bb = append(bb, r.toBalance(heldByCurrency[r.Currency]))
 	}
-	return r.toBalance(), nil
+	return bb, nil
 }
 
-func (repo *repository) ListTransactions(ctx context.Context, id int64) ([]*payment.Transaction, error) {
+func (repo *repository) ListTransactions(ctx context.Context, id int64, f payment.TransactionFilter) (*payment.TransactionPage, error) {
+	var account exp.Expression
+	switch {
+	case f.Direction == payment.DirectionIncoming && f.Counterparty != 0:
+		account = goqu.Ex{"to": id, "from": f.Counterparty}
+	case f.Direction == payment.DirectionOutgoing && f.Counterparty != 0:
+		account = goqu.Ex{"from": id, "to": f.Counterparty}
+	case f.Direction == payment.DirectionIncoming:
+		account = goqu.Ex{"to": id}
+	case f.Direction == payment.DirectionOutgoing:
+		account = goqu.Ex{"from": id}
+	case f.Counterparty != 0:
+		account = goqu.Or(goqu.Ex{"from": id, "to": f.Counterparty}, goqu.Ex{"to": id, "from": f.Counterparty})
+	default:
+		account = goqu.ExOr{"from": id, "to": id}
+	}
+	q := repo.gq.From(tableTransaction).Where(account)
+
+	if !f.Since.IsZero() {
+		q = q.Where(goqu.I("date").Gte(f.Since.UTC()))
+	}
+	if !f.Until.IsZero() {
+		q = q.Where(goqu.I("date").Lt(f.Until.UTC()))
+	}
+	if f.MinAmount != nil {
+		q = q.Where(goqu.I("amount").Gte(*f.MinAmount))
+	}
+	if f.MaxAmount != nil {
+		q = q.Where(goqu.I("amount").Lte(*f.MaxAmount))
+	}
+	if f.ExternalID != "" {
+		q = q.Where(goqu.I("external_id").Eq(f.ExternalID))
+	}
+	if len(f.Metadata) > 0 {
+		q = q.Where(goqu.L("? @> ?::jsonb", goqu.I("metadata"), f.Metadata.JSON()))
+	}
+
+	if f.Order == payment.OrderDesc {
+		if f.After != nil {
+			q = q.Where(goqu.L("(date, id) < (?, ?)", f.After.Date.UTC(), f.After.ID))
+		}
+		q = q.Order(goqu.I("date").Desc(), goqu.I("id").Desc())
+	} else {
+		if f.After != nil {
+			q = q.Where(goqu.L("(date, id) > (?, ?)", f.After.Date.UTC(), f.After.ID))
+		}
+		q = q.Order(goqu.I("date").Asc(), goqu.I("id").Asc())
+	}
+
+	// one extra record tells whether there is the next page
 	var rr []*recordTransaction
-	if err := repo.gq.From(tableTransaction).Where(goqu.ExOr{"from": id, "to": id}).Order(goqu.I("date").Asc()).ScanStructsContext(ctx, &rr); err != nil {
+	if err := q.Limit(uint(f.Limit+1)).ScanStructsContext(ctx, &rr); err != nil {
 		return nil, errors.Wrap(err, "unable to retrieve transaction records")
 	}
-	tt := make([]*payment.Transaction, 0, len(rr))
+	page := &payment.TransactionPage{Transactions: make([]*payment.Transaction, 0, len(rr))}
+	if len(rr) > f.Limit {
+		rr = rr[:f.Limit]
+		last := rr[len(rr)-1]
+		page.Next = &payment.Cursor{Date: last.Date, ID: last.ID}
+	}
 	for _, r := range rr {
-		tt = append(tt, r.toTransaction())
+		page.Transactions = append(page.Transactions, r.toTransaction())
 	}
 
-	return tt, nil
+	return page, nil
 }
 
-func lockBalance(tx *goqu.TxDatabase, accountID int64) error {
-	res := tx.Select(goqu.L(fmt.Sprintf("pg_try_advisory_xact_lock(%d)", accountID))).Executor()
-
-	var lock bool
-	if _, err := res.ScanVal(&lock); err != nil {
-		return errors.Wrapf(err, "unable to acquire lock for account with ID %d", accountID)
-	}
-	if !lock {
-		return errors.Errorf("unable to acquire lock for account with ID %d", accountID)
+// lockBalances acquire transaction level locks of accounts balances in ascending order of account ID,
+// so concurrent transfers A->B and B->A can't deadlock. System accounts aren't locked, their balances aren't cached
+func lockBalances(ctx context.Context, tx *goqu.TxDatabase, accountIDs ...int64) error {
+	ids := append([]int64(nil), accountIDs...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for i, id := range ids {
+		if (i > 0 && ids[i-1] == id) || payment.IsSystemAccount(id) {
+			continue
+		}
+		if err := lockBalance(ctx, tx, id); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func updateBalance(ctx context.Context, tx *goqu.TxDatabase, balance *recordBalance) error {
-	_, err := tx.Insert(tableBalance).Rows(balance).OnConflict(goqu.DoUpdate("account_id", balance)).Executor().ExecContext(ctx)
-	return err
+// lockBalance wait for lock of account balance until context deadline(or defaultLockWait when there is no deadline),
+// raise ErrAccountBusy when lock isn't acquired in time
+func lockBalance(ctx context.Context, tx *goqu.TxDatabase, accountID int64) error {
+	wait := defaultLockWait
+	if deadline, ok := ctx.Deadline(); ok {
+		wait = time.Until(deadline)
+	}
+	if wait < time.Millisecond {
+		return payment.ErrAccountBusy{ID: accountID}
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL lock_timeout = %d", wait.Milliseconds())); err != nil {
+		return errors.Wrap(err, "unable to set lock timeout")
+	}
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", accountID); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && (pqErr.Code == lockNotAvailable || pqErr.Code == queryCanceled) {
+			return payment.ErrAccountBusy{ID: accountID}
+		}
+		if ctx.Err() != nil {
+			return payment.ErrAccountBusy{ID: accountID}
+		}
+		return errors.Wrapf(err, "unable to acquire lock for account with ID %d", accountID)
+	}
+	return nil
 }
 
-func getBalance(ctx context.Context, tx *goqu.TxDatabase, id int64) (*recordBalance, error) {
+func getBalance(ctx context.Context, tx *goqu.TxDatabase, id int64, currency money.Currency) (*recordBalance, error) {
 	r := &recordBalance{}
-	found, err := tx.From(tableBalance).Where(goqu.I("account_id").Eq(id)).ScanStructContext(ctx, r)
+	found, err := tx.From(tableBalance).Where(goqu.Ex{"account_id": id, "currency": currency}).ScanStructContext(ctx, r)
 	if err != nil {
 		return nil, err
 	}
 	r.AccountID = id
+	r.Currency = currency
 	if !found {
 		return r, nil
 	}
@@ -134,67 +300,210 @@ func getBalance(ctx context.Context, tx *goqu.TxDatabase, id int64) (*recordBala
 }
 
 func (repo *repository) Transfer(ctx context.Context, t *payment.Transaction) (*payment.Transaction, error) {
-
-	r := fromTransaction(t)
 	err := repo.gq.WithTx(func(tx *goqu.TxDatabase) error {
+		return repo.idempotent(ctx, tx, t, func() error {
+			return transfer(ctx, tx, t)
+		})
+	})
+	return t, err
+}
 
-		if err := lockBalance(tx, t.From); err != nil {
-			return err
-		}
+func transfer(ctx context.Context, tx *goqu.TxDatabase, t *payment.Transaction) error {
+	if err := lockBalances(ctx, tx, t.From, t.To); err != nil {
+		return err
+	}
+	if err := checkAccounts(ctx, tx, t.From, t.To); err != nil {
+		return err
+	}
 
-		if err := lockBalance(tx, t.To); err != nil {
+	if t.QuoteID != 0 {
+		if err := useQuote(ctx, tx, t.QuoteID); err != nil {
 			return err
 		}
+	}
 
-		fromBalance, err := getBalance(ctx, tx, t.From)
-		if err != nil {
-			return err
-		}
-		if fromBalance.Balance < t.Amount {
-			return payment.ErrInsufficientFunds{ID: t.From}
-		}
+	if err := checkVelocity(ctx, tx, t.From, t.Currency, t.Amount, true); err != nil {
+		return err
+	}
+	if err := checkSpending(ctx, tx, t.From, t.Currency, t.Amount); err != nil {
+		return err
+	}
+	if err := checkFunds(ctx, tx, t.From, t.Currency, t.Amount); err != nil {
+		return err
+	}
 
-		fromBalance.Balance -= t.Amount
-		if err := updateBalance(ctx, tx, fromBalance); err != nil {
-			return err
-		}
+	if err := insertTransaction(ctx, tx, t); err != nil {
+		return err
+	}
+	return post(ctx, tx, &t.ID, t.Date, transferPostings(t)...)
+}
 
-		toBalance, err := getBalance(ctx, tx, t.To)
-		if err != nil {
-			return err
-		}
-		toBalance.Balance += t.Amount
-		if err := updateBalance(ctx, tx, toBalance); err != nil {
-			return err
+// transferPostings debit sender and credit recipient, cross-currency transfer goes through SystemExchange
+// so postings balance in both currencies
+func transferPostings(t *payment.Transaction) []payment.Posting {
+	if t.Currency == t.ToCurrency && t.Amount == t.ToAmount {
+		return []payment.Posting{
+			{AccountID: t.From, Currency: t.Currency, Amount: -t.Amount},
+			{AccountID: t.To, Currency: t.Currency, Amount: t.Amount},
 		}
+	}
+	return []payment.Posting{
+		{AccountID: t.From, Currency: t.Currency, Amount: -t.Amount},
+		{AccountID: payment.SystemExchange, Currency: t.Currency, Amount: t.Amount},
+		{AccountID: payment.SystemExchange, Currency: t.ToCurrency, Amount: -t.ToAmount},
+		{AccountID: t.To, Currency: t.ToCurrency, Amount: t.ToAmount},
+	}
+}
 
-		res := tx.From(tableTransaction).Insert().Returning(goqu.C("id")).Rows(r).Executor()
-		var id int64
-		if _, err := res.ScanVal(&id); err != nil {
-			return errors.Wrap(err, "failed to retrieve last inserted ID")
+// checkFunds raise ErrInsufficientFunds when available balance(ledger balance minus active holds) would drop below
+// overdraft or minimum balance of account wallet, zero by default, after debit of amount
+func checkFunds(ctx context.Context, tx *goqu.TxDatabase, accountID int64, currency money.Currency, amount money.Amount) error {
+	b, err := getBalance(ctx, tx, accountID, currency)
+	if err != nil {
+		return err
+	}
+	held, err := heldAmount(ctx, tx, accountID, currency)
+	if err != nil {
+		return err
+	}
+	l, err := getLimits(ctx, tx, accountID, currency)
+	if err != nil {
+		return err
+	}
+	// difference out of range is far below any floor
+	available, err := b.Balance.Sub(held)
+	if err == nil {
+		available, err = available.Sub(amount)
+	}
+	if err != nil || available < l.floor() {
+		return payment.ErrInsufficientFunds{ID: accountID}
+	}
+	return nil
+}
+
+func insertTransaction(ctx context.Context, tx *goqu.TxDatabase, t *payment.Transaction) error {
+	res := tx.From(tableTransaction).Insert().Returning(goqu.C("id")).Rows(fromTransaction(t)).Executor()
+	var id int64
+	if _, err := res.ScanValContext(ctx, &id); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolation {
+			return payment.ErrDuplicateExternalID{ExternalID: t.ExternalID}
 		}
-		t.ID = id
-		return nil
+		return errors.Wrap(err, "failed to retrieve last inserted ID")
+	}
+	t.ID = id
+	return nil
+}
+
+// TopUp - record top-up transaction funded by SystemFunding and return updated balance
+func (repo *repository) TopUp(ctx context.Context, t *payment.Transaction) (*payment.Balance, error) {
+	b := &payment.Balance{}
+	err := repo.gq.WithTx(func(tx *goqu.TxDatabase) error {
+		return repo.idempotent(ctx, tx, b, func() error {
+			if err := lockBalance(ctx, tx, t.To); err != nil {
+				return err
+			}
+			if err := checkAccounts(ctx, tx, t.To); err != nil {
+				return err
+			}
+			if err := insertTransaction(ctx, tx, t); err != nil {
+				return err
+			}
+			err := post(ctx, tx, &t.ID, t.Date,
+				payment.Posting{AccountID: t.From, Currency: t.Currency, Amount: -t.Amount},
+				payment.Posting{AccountID: t.To, Currency: t.Currency, Amount: t.Amount},
+			)
+			if err != nil {
+				return err
+			}
+			r, err := getBalance(ctx, tx, t.To, t.Currency)
+			if err != nil {
+				return err
+			}
+			held, err := heldAmount(ctx, tx, t.To, t.Currency)
+			if err != nil {
+				return err
+			}
+			*b = *r.toBalance(held)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Withdraw - record pending withdrawal and hold its funds on SystemPayoutPending, raise ErrInsufficientFunds
+func (repo *repository) Withdraw(ctx context.Context, t *payment.Transaction) (*payment.Transaction, error) {
+	err := repo.gq.WithTx(func(tx *goqu.TxDatabase) error {
+		return repo.idempotent(ctx, tx, t, func() error {
+			if err := lockBalance(ctx, tx, t.From); err != nil {
+				return err
+			}
+			if err := checkAccounts(ctx, tx, t.From); err != nil {
+				return err
+			}
+			if err := checkVelocity(ctx, tx, t.From, t.Currency, t.Amount, false); err != nil {
+				return err
+			}
+			if err := checkSpending(ctx, tx, t.From, t.Currency, t.Amount); err != nil {
+				return err
+			}
+			if err := checkFunds(ctx, tx, t.From, t.Currency, t.Amount); err != nil {
+				return err
+			}
+			if err := insertTransaction(ctx, tx, t); err != nil {
+				return err
+			}
+			return post(ctx, tx, &t.ID, t.Date,
+				payment.Posting{AccountID: t.From, Currency: t.Currency, Amount: -t.Amount},
+				payment.Posting{AccountID: payment.SystemPayoutPending, Currency: t.Currency, Amount: t.Amount},
+			)
+		})
 	})
 	return t, err
 }
 
-func (repo *repository) TopUp(ctx context.Context, accountID int64, amount float64) (*payment.Balance, error) {
-	var b *recordBalance
+// ConfirmWithdrawal - move held funds of pending withdrawal to SystemPayout when completed or back to the account when failed
+func (repo *repository) ConfirmWithdrawal(ctx context.Context, id int64, status payment.Status) (*payment.Transaction, error) {
+	var t *payment.Transaction
 	err := repo.gq.WithTx(func(tx *goqu.TxDatabase) error {
-		if err := lockBalance(tx, accountID); err != nil {
-			return err
-		}
-		var err error
-		b, err = getBalance(ctx, tx, accountID)
+		r := &recordTransaction{}
+		found, err := tx.From(tableTransaction).
+			Where(goqu.Ex{"id": id, "kind": payment.KindWithdrawal}).
+			ForUpdate(exp.Wait).
+			ScanStructContext(ctx, r)
 		if err != nil {
-			return err
+			return errors.Wrap(err, "unable to get withdrawal")
 		}
-		b.Balance += amount
-		if err := updateBalance(ctx, tx, b); err != nil {
+		if !found {
+			return payment.ErrTransactionNotFound{ID: id}
+		}
+		if r.Status != payment.StatusPending {
+			return payment.ErrNotPending{ID: id, Status: r.Status}
+		}
+
+		to := payment.SystemPayout
+		if status == payment.StatusFailed {
+			to = r.From
+			if err := lockBalance(ctx, tx, r.From); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.Update(tableTransaction).Set(goqu.Record{"status": status}).
+			Where(goqu.I("id").Eq(id)).Executor().ExecContext(ctx); err != nil {
+			return errors.Wrap(err, "unable to update withdrawal status")
+		}
+		err = post(ctx, tx, &id, time.Now().UTC(),
+			payment.Posting{AccountID: payment.SystemPayoutPending, Currency: r.Currency, Amount: -r.Amount},
+			payment.Posting{AccountID: to, Currency: r.Currency, Amount: r.Amount},
+		)
+		if err != nil {
 			return err
 		}
+		r.Status = status
+		t = r.toTransaction()
 		return nil
 	})
-	return b.toBalance(), err
+	return t, err
 }
@@ -0,0 +1,104 @@
+package pg
+
+import (
+	"coins/pkg/money"
+	"coins/pkg/payment"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGetBalance(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`FROM "balance"`).WillReturnRows(sqlmock.NewRows([]string{"account_id", "currency", "balance"}).
+		AddRow(1, "EUR", "100.00").
+		AddRow(1, "USD", "20.50"))
+	mock.ExpectQuery(`FROM "hold"`).WillReturnRows(sqlmock.NewRows([]string{"currency", "amount"}).
+		AddRow("USD", "5.25"))
+
+	got, err := NewRepository(db).GetBalance(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*payment.Balance{
+		{AccountID: 1, Currency: "EUR", Ledger: 10000, Available: 10000},
+		{AccountID: 1, Currency: "USD", Ledger: 2050, Available: 1525},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTopUpRecordsTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectExec(`SET LOCAL lock_timeout`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`FROM "account"`).WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(1, "active"))
+	mock.ExpectQuery(`INSERT INTO "transaction" .* VALUES \('10.00', 'EUR', '[^']+', NULL, -1, FALSE, 'topup', .*'completed', 1, '10.00', 'EUR'\) RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectExec(`INSERT INTO "posting" .* VALUES \(-1, '-10.00', 'EUR', '[^']+', 5\), \(1, '10.00', 'EUR', '[^']+', 5\)`).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(`FROM "balance"`).WillReturnRows(sqlmock.NewRows([]string{"account_id", "currency", "balance"}))
+	mock.ExpectExec(`INSERT INTO "balance"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`FROM "balance"`).WillReturnRows(sqlmock.NewRows([]string{"account_id", "currency", "balance"}).AddRow(1, "EUR", "10.00"))
+	mock.ExpectQuery(`FROM "hold"`).WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow("0"))
+	mock.ExpectCommit()
+
+	tx := &payment.Transaction{Kind: payment.KindTopUp, Status: payment.StatusCompleted, From: payment.SystemFunding, To: 1,
+		Amount: 1000, Currency: "EUR", ToAmount: 1000, ToCurrency: "EUR", Rate: money.OneRate, Date: time.Now().UTC()}
+	b, err := NewRepository(db).TopUp(context.Background(), tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.ID != 5 {
+		t.Errorf("got transaction ID %d, want 5", tx.ID)
+	}
+	if want := (payment.Balance{AccountID: 1, Currency: "EUR", Ledger: 1000, Available: 1000}); *b != want {
+		t.Errorf("got balance %+v, want %+v", *b, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfirmWithdrawalNotPending(t *testing.T) {
+	tests := []struct {
+		name string
+		rows *sqlmock.Rows
+		want error
+	}{
+		{"not found", sqlmock.NewRows([]string{"id", "status"}), payment.ErrTransactionNotFound{ID: 1}},
+		{"completed", sqlmock.NewRows([]string{"id", "status"}).AddRow(1, "completed"), payment.ErrNotPending{ID: 1, Status: payment.StatusCompleted}},
+		{"failed", sqlmock.NewRows([]string{"id", "status"}).AddRow(1, "failed"), payment.ErrNotPending{ID: 1, Status: payment.StatusFailed}},
+	}
+	for _, tt := range tests {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM "transaction" WHERE \(\("id" = 1\) AND \("kind" = 'withdrawal'\)\).* FOR UPDATE`).WillReturnRows(tt.rows)
+		mock.ExpectRollback()
+		if _, err := NewRepository(db).ConfirmWithdrawal(context.Background(), 1, payment.StatusCompleted); err != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		db.Close()
+	}
+}
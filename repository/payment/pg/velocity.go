@@ -0,0 +1,92 @@
+package pg
+
+import (
+	"coins/pkg/money"
+	"coins/pkg/payment"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/doug-martin/goqu/v8"
+	"github.com/doug-martin/goqu/v8/exp"
+	"github.com/pkg/errors"
+)
+
+// rolling windows of outflow, shared by velocity limits of tier and spending limits of wallet
+const (
+	dailyWindow   = 24 * time.Hour
+	monthlyWindow = 30 * dailyWindow
+)
+
+// checkVelocity raise ErrLimitExceeded when moving amount out of account breaks velocity limit of its tier carried
+// by ctx, transfer is true for outgoing transfers counted by TransfersPerMinute. Balance of account must be locked,
+// so concurrent requests can't pass the limit together
+func checkVelocity(ctx context.Context, tx *goqu.TxDatabase, accountID int64, currency money.Currency, amount money.Amount, transfer bool) error {
+	l, ok := payment.VelocityLimitFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	now := time.Now().UTC()
+	if transfer && l.TransfersPerMinute > 0 {
+		n, err := tx.From(tableTransaction).
+			Where(goqu.Ex{"from": accountID, "kind": payment.KindTransfer}, goqu.I("date").Gte(now.Add(-time.Minute))).
+			CountContext(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to count transfers")
+		}
+		if int(n) >= l.TransfersPerMinute {
+			return payment.ErrLimitExceeded{ID: accountID, Limit: payment.LimitTransfersPerMinute, Max: strconv.Itoa(l.TransfersPerMinute)}
+		}
+	}
+	if max, ok := l.DailyOutflow[currency]; ok {
+		sum, err := sumCommittedOutflow(ctx, tx, accountID, currency, now.Add(-dailyWindow))
+		if err != nil {
+			return err
+		}
+		if total, err := sum.Add(amount); err != nil || total > max {
+			return payment.ErrLimitExceeded{ID: accountID, Limit: payment.LimitDailyOutflow, Max: max.String() + " " + string(currency)}
+		}
+	}
+	return nil
+}
+
+// sumOutflow return amount of currency moved out of account by transfers, captures and withdrawals since
+func sumOutflow(ctx context.Context, tx *goqu.TxDatabase, accountID int64, currency money.Currency, since time.Time) (money.Amount, error) {
+	var sum money.Amount
+	_, err := tx.From(tableTransaction).
+		Select(goqu.COALESCE(goqu.SUM("amount"), 0)).
+		Where(outflow(accountID, currency, since)).
+		ScanValContext(ctx, &sum)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to sum outflow")
+	}
+	return sum, nil
+}
+
+// sumCommittedOutflow return outflow of account since with amount of its active holds, which leaves the account
+// when captured. Captures aren't checked again, so every hold is counted once: while active or as its capture
+func sumCommittedOutflow(ctx context.Context, tx *goqu.TxDatabase, accountID int64, currency money.Currency, since time.Time) (money.Amount, error) {
+	sum, err := sumOutflow(ctx, tx, accountID, currency, since)
+	if err != nil {
+		return 0, err
+	}
+	held, err := heldAmount(ctx, tx, accountID, currency)
+	if err != nil {
+		return 0, err
+	}
+	return sum.Add(held)
+}
+
+// outflow select transactions moving currency out of account since, failed withdrawals returned their funds
+// and aren't selected
+func outflow(accountID int64, currency money.Currency, since time.Time) exp.ExpressionList {
+	return goqu.And(
+		goqu.Ex{
+			"from":     accountID,
+			"currency": currency,
+			"kind":     []payment.Kind{payment.KindTransfer, payment.KindCapture, payment.KindWithdrawal},
+		},
+		goqu.I("status").Neq(payment.StatusFailed),
+		goqu.I("date").Gte(since),
+	)
+}